@@ -2,19 +2,192 @@ package cmd
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Yunsang-Jeong/terraform-mcp-server/pkg/utils/gitrepo"
+	"github.com/Yunsang-Jeong/terraform-mcp-server/pkg/utils/localdir"
+	"github.com/Yunsang-Jeong/terraform-mcp-server/pkg/utils/providerschema"
+	"github.com/Yunsang-Jeong/terraform-mcp-server/pkg/utils/registry"
 
 	"github.com/charmbracelet/fang"
 	"github.com/spf13/cobra"
 )
 
+var (
+	registryHost      string
+	registryURLs      []string
+	registryTokens    []string
+	registryUserAgent string
+	credentialsFile   string
+	cliConfigFile     string
+	cacheDir          string
+	noCache           bool
+	memCacheEntries   int
+	memCacheBytes     int64
+	memCacheTTL       time.Duration
+	transportConfig   = registry.DefaultTransportConfig()
+	registryTimeout   time.Duration
+	schemaFiles       []string
+	gitTokens         []string
+	netrcFile         string
+	sshKeyFiles       []string
+	knownHostsFiles   []string
+	gitCacheDir       string
+	noGitCache        bool
+	gitCacheMaxBytes  int64
+	gitCacheMaxAge    time.Duration
+	localRoots        []string
+)
+
 var rootCmd = &cobra.Command{
 	Use:           "terraform-mcp-server",
 	Short:         "Terraform MCP Server",
 	Long:          "Terraform MCP Server - Provides Terraform module and provider documentation via MCP protocol",
 	SilenceErrors: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := configureRegistry(); err != nil {
+			return err
+		}
+		if err := configureGit(); err != nil {
+			return err
+		}
+		if err := localdir.Configure(localRoots); err != nil {
+			return err
+		}
+		return loadProviderSchemas()
+	},
+}
+
+func init() {
+	flags := rootCmd.PersistentFlags()
+	flags.StringVar(&registryHost, "registry-host", registry.DEFAULT_REGISTRY_HOST, "default registry host used when a tool does not name one")
+	flags.StringArrayVar(&registryURLs, "registry-url", nil, "override the base URL of a registry host (host=url, repeatable)")
+	flags.StringArrayVar(&registryTokens, "registry-token", nil, "bearer token for a registry host (host=token, repeatable)")
+	flags.StringVar(&registryUserAgent, "user-agent", registry.DEFAULT_USER_AGENT, "user agent sent to registries")
+
+	credentials := registry.DefaultCredentialsSources()
+	flags.StringVar(&credentialsFile, "credentials-file", credentials.CredentialsFile, "terraform credentials.tfrc.json to read registry tokens from")
+	flags.StringVar(&cliConfigFile, "cli-config-file", credentials.CLIConfigFile, "terraform CLI config file to read credentials blocks from")
+
+	flags.StringVar(&cacheDir, "cache-dir", registry.DefaultCacheDir(), "directory for the on-disk registry response cache")
+	flags.BoolVar(&noCache, "no-cache", false, "disable the on-disk registry response cache")
+
+	flags.IntVar(&memCacheEntries, "memory-cache-entries", registry.DEFAULT_MEMORY_CACHE_ENTRIES, "maximum registry responses kept in memory (0 disables the memory cache)")
+	flags.Int64Var(&memCacheBytes, "memory-cache-bytes", registry.DEFAULT_MEMORY_CACHE_BYTES, "maximum total size of registry responses kept in memory")
+	flags.DurationVar(&memCacheTTL, "memory-cache-ttl", registry.DEFAULT_MEMORY_CACHE_TTL, "how long registry responses are kept in memory")
+
+	flags.IntVar(&transportConfig.MaxRetries, "registry-max-retries", transportConfig.MaxRetries, "retries for 429, 5xx and network errors from registries")
+	flags.DurationVar(&transportConfig.MinBackoff, "registry-min-backoff", transportConfig.MinBackoff, "initial backoff between registry retries")
+	flags.DurationVar(&transportConfig.MaxBackoff, "registry-max-backoff", transportConfig.MaxBackoff, "maximum backoff between registry retries, also caps Retry-After")
+	flags.Float64Var(&transportConfig.RateLimit, "registry-rate-limit", transportConfig.RateLimit, "requests per second allowed per registry host (0 disables rate limiting)")
+	flags.IntVar(&transportConfig.RateBurst, "registry-rate-burst", transportConfig.RateBurst, "requests allowed in a burst per registry host")
+	flags.DurationVar(&transportConfig.AttemptTimeout, "registry-attempt-timeout", transportConfig.AttemptTimeout, "how long a single registry attempt waits for a response")
+	flags.DurationVar(&registryTimeout, "registry-timeout", registry.DEFAULT_TIMEOUT, "overall timeout of a registry request including retries")
+
+	flags.StringArrayVar(&schemaFiles, "provider-schema-file", nil, "output of 'terraform providers schema -json' to load at startup (repeatable)")
+
+	flags.StringArrayVar(&gitTokens, "git-token", nil, "HTTPS token for a git host (host=token, repeatable)")
+	flags.StringVar(&netrcFile, "netrc-file", gitrepo.DefaultNetrcFile(), "netrc file to read git HTTPS logins from")
+	flags.StringArrayVar(&sshKeyFiles, "ssh-key-file", nil, "unencrypted private key for SSH git sources, offered after ssh-agent keys (repeatable, default ~/.ssh/id_*)")
+	flags.StringArrayVar(&knownHostsFiles, "ssh-known-hosts-file", nil, "known_hosts file verifying SSH git hosts (repeatable, default $SSH_KNOWN_HOSTS or ~/.ssh/known_hosts)")
+
+	flags.StringVar(&gitCacheDir, "git-cache-dir", gitrepo.DefaultCacheDir(), "directory for the git mirrors modules are checked out of")
+	flags.BoolVar(&noGitCache, "no-git-cache", false, "disable the git mirror cache and clone on every call")
+	flags.Int64Var(&gitCacheMaxBytes, "git-cache-max-bytes", gitrepo.DEFAULT_CACHE_MAX_BYTES, "maximum total size of the git mirrors (0 disables the bound)")
+	flags.DurationVar(&gitCacheMaxAge, "git-cache-max-age", gitrepo.DEFAULT_CACHE_MAX_AGE, "how long an unused git mirror is kept (0 disables the bound)")
+
+	flags.StringArrayVar(&localRoots, "local-root", nil, "directory get_local_module and load_provider_schema may read files from (repeatable; local reads are disabled without one)")
+}
+
+func configureRegistry() error {
+	hosts, err := parseKeyValues("registry-url", registryURLs)
+	if err != nil {
+		return err
+	}
+
+	tokens, err := registry.LoadCredentials(registry.CredentialsSources{
+		CredentialsFile: credentialsFile,
+		CLIConfigFile:   cliConfigFile,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Tokens given on the command line take precedence over Terraform CLI credentials
+	explicitTokens, err := parseKeyValues("registry-token", registryTokens)
+	if err != nil {
+		return err
+	}
+	for host, token := range explicitTokens {
+		tokens[host] = token
+	}
+
+	cfg := registry.Config{
+		DefaultHost: registryHost,
+		Hosts:       hosts,
+		UserAgent:   registryUserAgent,
+		Tokens:      tokens,
+
+		MemoryCacheEntries: memCacheEntries,
+		MemoryCacheBytes:   memCacheBytes,
+		MemoryCacheTTL:     memCacheTTL,
+
+		Transport: transportConfig,
+		Timeout:   registryTimeout,
+	}
+	if !noCache {
+		cfg.CacheDir = cacheDir
+	}
+
+	return registry.Configure(cfg)
+}
+
+func configureGit() error {
+	tokens, err := parseKeyValues("git-token", gitTokens)
+	if err != nil {
+		return err
+	}
+
+	cfg := gitrepo.Config{
+		Tokens:          tokens,
+		NetrcFile:       netrcFile,
+		SSHKeyFiles:     sshKeyFiles,
+		KnownHostsFiles: knownHostsFiles,
+
+		CacheMaxBytes: gitCacheMaxBytes,
+		CacheMaxAge:   gitCacheMaxAge,
+	}
+	if !noGitCache {
+		cfg.CacheDir = gitCacheDir
+	}
+
+	return gitrepo.Configure(cfg)
 }
 
-func init() {}
+// loadProviderSchemas loads the provider schema files given on the command line
+func loadProviderSchemas() error {
+	for _, path := range schemaFiles {
+		if _, err := providerschema.Default().LoadFile(path); err != nil {
+			return fmt.Errorf("failed to load provider schema %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// parseKeyValues parses repeated "key=value" flag values
+func parseKeyValues(flag string, values []string) (map[string]string, error) {
+	result := map[string]string{}
+	for _, v := range values {
+		key, value, ok := strings.Cut(v, "=")
+		if !ok || key == "" || value == "" {
+			return nil, fmt.Errorf("invalid --%s value %q: expected key=value", flag, v)
+		}
+		result[key] = value
+	}
+	return result, nil
+}
 
 func Execute(ctx context.Context) error {
 	// Remove help for root command
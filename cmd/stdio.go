@@ -12,7 +12,7 @@ var stdioCmd = &cobra.Command{
 This mode is typically used when the server is invoked by an MCP client
 that communicates via standard input and output streams.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return server.RunStdio()
+		return server.RunStdio(cmd.Context())
 	},
 }
 
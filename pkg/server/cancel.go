@@ -0,0 +1,90 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	METHOD_NOTIFICATION_CANCELLED = "notifications/cancelled"
+
+	requestIdMetaKey = "terraform-mcp-server/request-id"
+)
+
+// requestCanceller aborts in-flight tool calls when the client sends
+// notifications/cancelled for their request ID
+type requestCanceller struct {
+	mu      sync.Mutex
+	cancels map[string]context.CancelFunc
+}
+
+func newRequestCanceller() *requestCanceller {
+	return &requestCanceller{cancels: map[string]context.CancelFunc{}}
+}
+
+// rememberRequestId records the JSON-RPC request ID on the tool call, since
+// tool handlers are not given it otherwise
+func (c *requestCanceller) rememberRequestId(ctx context.Context, id any, request *mcp.CallToolRequest) {
+	if request.Params.Meta == nil {
+		request.Params.Meta = &mcp.Meta{}
+	}
+	if request.Params.Meta.AdditionalFields == nil {
+		request.Params.Meta.AdditionalFields = map[string]any{}
+	}
+	request.Params.Meta.AdditionalFields[requestIdMetaKey] = id
+}
+
+// middleware runs each tool call with a context that is cancelled by a matching notification
+func (c *requestCanceller) middleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if request.Params.Meta == nil || request.Params.Meta.AdditionalFields[requestIdMetaKey] == nil {
+			return next(ctx, request)
+		}
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		key := requestKey(ctx, request.Params.Meta.AdditionalFields[requestIdMetaKey])
+
+		c.mu.Lock()
+		c.cancels[key] = cancel
+		c.mu.Unlock()
+
+		defer func() {
+			c.mu.Lock()
+			delete(c.cancels, key)
+			c.mu.Unlock()
+		}()
+
+		return next(ctx, request)
+	}
+}
+
+// handleCancelled handles notifications/cancelled sent by the client
+func (c *requestCanceller) handleCancelled(ctx context.Context, notification mcp.JSONRPCNotification) {
+	id, ok := notification.Params.AdditionalFields["requestId"]
+	if !ok {
+		return
+	}
+
+	c.mu.Lock()
+	cancel, ok := c.cancels[requestKey(ctx, id)]
+	c.mu.Unlock()
+
+	if ok {
+		cancel()
+	}
+}
+
+// requestKey scopes a request ID to its session, since IDs are only unique per client
+func requestKey(ctx context.Context, id any) string {
+	sessionId := ""
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		sessionId = session.SessionID()
+	}
+	return fmt.Sprintf("%s/%v", sessionId, id)
+}
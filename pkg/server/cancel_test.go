@@ -0,0 +1,58 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestRequestCanceller(t *testing.T) {
+	canceller := newRequestCanceller()
+
+	hooks := &server.Hooks{}
+	hooks.AddBeforeCallTool(canceller.rememberRequestId)
+
+	s := server.NewMCPServer("test", "0.0.0",
+		server.WithToolCapabilities(false),
+		server.WithHooks(hooks),
+		server.WithToolHandlerMiddleware(canceller.middleware),
+	)
+	s.AddNotificationHandler(METHOD_NOTIFICATION_CANCELLED, canceller.handleCancelled)
+
+	started := make(chan struct{})
+	s.AddTool(mcp.NewTool("block"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		close(started)
+		select {
+		case <-ctx.Done():
+			return mcp.NewToolResultError(ctx.Err().Error()), nil
+		case <-time.After(5 * time.Second):
+			return mcp.NewToolResultText("not cancelled"), nil
+		}
+	})
+
+	done := make(chan mcp.JSONRPCMessage, 1)
+	go func() {
+		done <- s.HandleMessage(context.Background(), json.RawMessage(`{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"block"}}`))
+	}()
+
+	<-started
+	s.HandleMessage(context.Background(), json.RawMessage(`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":7,"reason":"user abort"}}`))
+
+	select {
+	case msg := <-done:
+		resp, ok := msg.(mcp.JSONRPCResponse)
+		if !ok {
+			t.Fatalf("unexpected response type %T", msg)
+		}
+		result, ok := resp.Result.(mcp.CallToolResult)
+		if !ok || !result.IsError {
+			t.Errorf("expected cancelled tool call to return an error result, got %+v", resp.Result)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("tool call was not cancelled")
+	}
+}
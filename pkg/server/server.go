@@ -14,11 +14,19 @@ import (
 
 // createMCPServer creates and configures the MCP server with all tools
 func createMCPServer() *server.MCPServer {
+	canceller := newRequestCanceller()
+
+	hooks := &server.Hooks{}
+	hooks.AddBeforeCallTool(canceller.rememberRequestId)
+
 	s := server.NewMCPServer(
 		"Terraform MCP Server",
 		version.Version,
 		server.WithToolCapabilities(false),
+		server.WithHooks(hooks),
+		server.WithToolHandlerMiddleware(canceller.middleware),
 	)
+	s.AddNotificationHandler(METHOD_NOTIFICATION_CANCELLED, canceller.handleCancelled)
 
 	s.AddTool(mcp.NewTool("search_resource_block_document",
 		mcp.WithDescription("특정 버전의 resource block 설명을 가져옵니다."),
@@ -26,16 +34,21 @@ func createMCPServer() *server.MCPServer {
 			mcp.Description("provider의 namespace 입니다. 기본값은 'hashicorp' 입니다."),
 		),
 		mcp.WithString("provider_name",
-			mcp.Description("provider의 name 입니다. 예: 'aws', 'azurerm'."),
+			mcp.Description("provider의 name 입니다. 예: 'aws', 'azurerm'. 'app.terraform.io/acme/aws' 처럼 전체 source 주소를 입력하면 해당 registry host를 사용합니다."),
 			mcp.Required(),
 		),
 		mcp.WithString("provider_version",
-			mcp.Description("provider의 version 입니다. 최신버전은 생략하거나 공백을 입력합니다."),
+			mcp.Description("provider의 version 입니다. '5.0.0' 같은 정확한 버전 또는 '~> 5.0' 같은 version constraint를 입력할 수 있습니다. 최신버전은 생략하거나 공백을 입력합니다."),
 		),
 		mcp.WithString("block_name",
-			mcp.Description("확인하려는 block의 name 입니다. 예: 's3_bucket'."),
+			mcp.Description("확인하려는 block의 name 입니다. 예: 's3_bucket'. 'aws_s3_bucket' 처럼 provider prefix를 붙여도 됩니다. 일치하는 문서가 없으면 비슷한 이름을 제안합니다."),
 			mcp.Required(),
 		),
+		mcp.WithString("output",
+			mcp.Description("출력 형식 입니다. 'markdown'(기본값)은 문서 원문을, 'schema'는 arguments, nested blocks, attributes, timeouts, import를 정리한 JSON을 반환합니다. toc, sections, max_bytes는 markdown 출력에만 적용됩니다."),
+			mcp.Enum("markdown", "schema"),
+		),
+		withDocumentView(),
 	), tools.GetResourceBlockDocument)
 
 	s.AddTool(mcp.NewTool("search_data_block_document",
@@ -44,35 +57,394 @@ func createMCPServer() *server.MCPServer {
 			mcp.Description("provider의 namespace 입니다. 기본값은 'hashicorp' 입니다."),
 		),
 		mcp.WithString("provider_name",
-			mcp.Description("provider의 name 입니다. 예: 'aws', 'azurerm'."),
+			mcp.Description("provider의 name 입니다. 예: 'aws', 'azurerm'. 'app.terraform.io/acme/aws' 처럼 전체 source 주소를 입력하면 해당 registry host를 사용합니다."),
 			mcp.Required(),
 		),
 		mcp.WithString("provider_version",
-			mcp.Description("provider의 version 입니다. 최신버전은 생략하거나 공백을 입력합니다."),
+			mcp.Description("provider의 version 입니다. '5.0.0' 같은 정확한 버전 또는 '~> 5.0' 같은 version constraint를 입력할 수 있습니다. 최신버전은 생략하거나 공백을 입력합니다."),
 		),
 		mcp.WithString("block_name",
-			mcp.Description("확인하려는 block의 name 입니다. 예: 's3_bucket'."),
+			mcp.Description("확인하려는 block의 name 입니다. 예: 's3_bucket'. 'aws_s3_bucket' 처럼 provider prefix를 붙여도 됩니다. 일치하는 문서가 없으면 비슷한 이름을 제안합니다."),
 			mcp.Required(),
 		),
+		mcp.WithString("output",
+			mcp.Description("출력 형식 입니다. 'markdown'(기본값)은 문서 원문을, 'schema'는 arguments, nested blocks, attributes, timeouts, import를 정리한 JSON을 반환합니다. toc, sections, max_bytes는 markdown 출력에만 적용됩니다."),
+			mcp.Enum("markdown", "schema"),
+		),
+		withDocumentView(),
 	), tools.GetDataBlockDocument)
 
+	s.AddTool(mcp.NewTool("list_provider_versions",
+		mcp.WithDescription("provider의 버전 목록과 배포일을 가져오고, version constraint에 맞는 버전을 찾습니다."),
+		mcp.WithString("provider_namespace",
+			mcp.Description("provider의 namespace 입니다. 기본값은 'hashicorp' 입니다."),
+		),
+		mcp.WithString("provider_name",
+			mcp.Description("provider의 name 입니다. 예: 'aws', 'azurerm'. 'app.terraform.io/acme/aws' 처럼 전체 source 주소를 입력하면 해당 registry host를 사용합니다."),
+			mcp.Required(),
+		),
+		mcp.WithString("constraint",
+			mcp.Description("Terraform version constraint 입니다. 예: '~> 5.0', '>= 4.2, < 6'. 입력하면 조건에 맞는 버전만 반환하고 가장 높은 버전을 'resolved'로 알려줍니다."),
+		),
+	), tools.ListProviderVersions)
+
+	s.AddTool(mcp.NewTool("list_provider_docs",
+		mcp.WithDescription("provider 문서 목록(category, subcategory, slug, title)을 가져옵니다. block_name에 사용할 slug를 찾을 때 사용합니다."),
+		mcp.WithString("provider_namespace",
+			mcp.Description("provider의 namespace 입니다. 기본값은 'hashicorp' 입니다."),
+		),
+		mcp.WithString("provider_name",
+			mcp.Description("provider의 name 입니다. 예: 'aws', 'azurerm'. 'app.terraform.io/acme/aws' 처럼 전체 source 주소를 입력하면 해당 registry host를 사용합니다."),
+			mcp.Required(),
+		),
+		mcp.WithString("provider_version",
+			mcp.Description("provider의 version 또는 version constraint 입니다. 최신버전은 생략하거나 공백을 입력합니다."),
+		),
+		mcp.WithString("category",
+			mcp.Description("문서 category 입니다. 예: 'resources', 'data-sources', 'ephemeral-resources', 'functions', 'guides'. 생략하면 모든 category를 반환합니다."),
+		),
+		mcp.WithString("subcategory",
+			mcp.Description("문서 subcategory 입니다. 예: 'S3 (Simple Storage)'. 생략하면 모든 subcategory를 반환합니다."),
+		),
+	), tools.ListProviderDocs)
+
+	s.AddTool(mcp.NewTool("search_provider_docs",
+		mcp.WithDescription("provider 문서의 slug와 title을 검색합니다. provider prefix가 붙은 이름, 오타, 약어(예: 'sg', 'vm')도 찾아줍니다."),
+		mcp.WithString("provider_namespace",
+			mcp.Description("provider의 namespace 입니다. 기본값은 'hashicorp' 입니다."),
+		),
+		mcp.WithString("provider_name",
+			mcp.Description("provider의 name 입니다. 예: 'aws', 'azurerm'. 'app.terraform.io/acme/aws' 처럼 전체 source 주소를 입력하면 해당 registry host를 사용합니다."),
+			mcp.Required(),
+		),
+		mcp.WithString("provider_version",
+			mcp.Description("provider의 version 또는 version constraint 입니다. 최신버전은 생략하거나 공백을 입력합니다."),
+		),
+		mcp.WithString("query",
+			mcp.Description("검색어 입니다. 예: 'aws_s3_bucket', 's3 bucket policy'."),
+			mcp.Required(),
+		),
+		mcp.WithString("category",
+			mcp.Description("검색할 문서 category 입니다. 예: 'resources', 'data-sources'. 생략하면 모든 category를 검색합니다."),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("반환할 최대 결과 수 입니다. 기본값은 10 입니다."),
+		),
+	), tools.SearchProviderDocs)
+
+	s.AddTool(mcp.NewTool("get_provider_document",
+		mcp.WithDescription("provider 문서를 category와 slug로 가져옵니다. guides, functions, ephemeral-resources, list-resources 등 모든 category를 지원합니다."),
+		mcp.WithString("provider_namespace",
+			mcp.Description("provider의 namespace 입니다. 기본값은 'hashicorp' 입니다."),
+		),
+		mcp.WithString("provider_name",
+			mcp.Description("provider의 name 입니다. 예: 'aws', 'azurerm'. 'app.terraform.io/acme/aws' 처럼 전체 source 주소를 입력하면 해당 registry host를 사용합니다."),
+			mcp.Required(),
+		),
+		mcp.WithString("provider_version",
+			mcp.Description("provider의 version 또는 version constraint 입니다. 최신버전은 생략하거나 공백을 입력합니다."),
+		),
+		mcp.WithString("category",
+			mcp.Description("문서 category 입니다. 예: 'overview', 'resources', 'data-sources', 'ephemeral-resources', 'list-resources', 'functions', 'guides'. provider가 제공하지 않는 category면 사용 가능한 category를 알려줍니다."),
+			mcp.Required(),
+		),
+		mcp.WithString("slug",
+			mcp.Description("문서의 slug 입니다. 예: 's3_bucket', 'version-5-upgrade'. list_provider_docs로 확인할 수 있습니다."),
+			mcp.Required(),
+		),
+		withDocumentView(),
+	), tools.GetProviderDocument)
+
+	s.AddTool(mcp.NewTool("get_provider_function",
+		mcp.WithDescription("provider-defined function 설명을 가져옵니다."),
+		mcp.WithString("provider_namespace",
+			mcp.Description("provider의 namespace 입니다. 기본값은 'hashicorp' 입니다."),
+		),
+		mcp.WithString("provider_name",
+			mcp.Description("provider의 name 입니다. 예: 'aws', 'azurerm'. 'app.terraform.io/acme/aws' 처럼 전체 source 주소를 입력하면 해당 registry host를 사용합니다."),
+			mcp.Required(),
+		),
+		mcp.WithString("provider_version",
+			mcp.Description("provider의 version 또는 version constraint 입니다. 최신버전은 생략하거나 공백을 입력합니다."),
+		),
+		mcp.WithString("function_name",
+			mcp.Description("function의 name 입니다. 예: 'arn_parse'. 'provider::aws::arn_parse' 처럼 호출 형식으로 입력해도 됩니다."),
+			mcp.Required(),
+		),
+		withDocumentView(),
+	), tools.GetProviderFunction)
+
+	s.AddTool(mcp.NewTool("get_provider_guide",
+		mcp.WithDescription("provider guide 문서를 가져옵니다. guide_name을 생략하면 해당 버전의 major upgrade guide를 가져옵니다."),
+		mcp.WithString("provider_namespace",
+			mcp.Description("provider의 namespace 입니다. 기본값은 'hashicorp' 입니다."),
+		),
+		mcp.WithString("provider_name",
+			mcp.Description("provider의 name 입니다. 예: 'aws', 'azurerm'. 'app.terraform.io/acme/aws' 처럼 전체 source 주소를 입력하면 해당 registry host를 사용합니다."),
+			mcp.Required(),
+		),
+		mcp.WithString("provider_version",
+			mcp.Description("provider의 version 또는 version constraint 입니다. 최신버전은 생략하거나 공백을 입력합니다. 예: '~> 5.0' 이면 version 5 upgrade guide를 찾습니다."),
+		),
+		mcp.WithString("guide_name",
+			mcp.Description("guide의 slug 또는 title 입니다. 예: 'custom-service-endpoints'. 생략하면 upgrade guide를 찾습니다."),
+		),
+		withDocumentView(),
+	), tools.GetProviderGuide)
+
+	s.AddTool(mcp.NewTool("get_provider_examples",
+		mcp.WithDescription("provider 문서의 예제 코드(terraform/hcl code block)만 추출합니다. 각 예제에는 위치한 heading과 HCL 문법 검사 결과가 포함됩니다."),
+		mcp.WithString("provider_namespace",
+			mcp.Description("provider의 namespace 입니다. 기본값은 'hashicorp' 입니다."),
+		),
+		mcp.WithString("provider_name",
+			mcp.Description("provider의 name 입니다. 예: 'aws', 'azurerm'. 'app.terraform.io/acme/aws' 처럼 전체 source 주소를 입력하면 해당 registry host를 사용합니다."),
+			mcp.Required(),
+		),
+		mcp.WithString("provider_version",
+			mcp.Description("provider의 version 또는 version constraint 입니다. 최신버전은 생략하거나 공백을 입력합니다."),
+		),
+		mcp.WithString("category",
+			mcp.Description("문서 category 입니다. 기본값은 'resources' 입니다. 예: 'data-sources', 'ephemeral-resources', 'functions'."),
+		),
+		mcp.WithString("block_name",
+			mcp.Description("예제를 가져올 문서의 name 입니다. 예: 's3_bucket', 'aws_s3_bucket'."),
+			mcp.Required(),
+		),
+	), tools.GetProviderExamples)
+
+	s.AddTool(mcp.NewTool("diff_provider_document",
+		mcp.WithDescription("두 provider 버전 사이의 문서 변경사항을 비교합니다. 추가/삭제/deprecated/required로 바뀐 argument와 attribute 목록과 unified diff를 반환합니다."),
+		mcp.WithString("provider_namespace",
+			mcp.Description("provider의 namespace 입니다. 기본값은 'hashicorp' 입니다."),
+		),
+		mcp.WithString("provider_name",
+			mcp.Description("provider의 name 입니다. 예: 'aws', 'azurerm'. 'app.terraform.io/acme/aws' 처럼 전체 source 주소를 입력하면 해당 registry host를 사용합니다."),
+			mcp.Required(),
+		),
+		mcp.WithString("category",
+			mcp.Description("문서 category 입니다. 기본값은 'resources' 입니다. 예: 'data-sources'."),
+		),
+		mcp.WithString("block_name",
+			mcp.Description("비교할 문서의 name 입니다. 예: 's3_bucket', 'aws_s3_bucket'."),
+			mcp.Required(),
+		),
+		mcp.WithString("from_version",
+			mcp.Description("비교 기준 version 또는 version constraint 입니다. 예: '3.76.1', '~> 3.0'."),
+			mcp.Required(),
+		),
+		mcp.WithString("to_version",
+			mcp.Description("비교 대상 version 또는 version constraint 입니다. 예: '5.31.0', '~> 5.0'."),
+			mcp.Required(),
+		),
+		mcp.WithBoolean("unified_diff",
+			mcp.Description("문서 원문의 unified diff를 포함할지 여부 입니다. 기본값은 true 입니다."),
+		),
+		mcp.WithNumber("context",
+			mcp.Description("unified diff에서 변경된 줄 앞뒤로 보여줄 줄 수 입니다. 기본값은 3 입니다."),
+		),
+	), tools.DiffProviderDocument)
+
+	s.AddTool(mcp.NewTool("check_deprecations",
+		mcp.WithDescription("여러 resource/data source가 특정 provider 버전에서 deprecated 되었거나 삭제되었는지 문서를 기준으로 확인합니다. upgrade guide에서 언급된 section도 알려줍니다."),
+		mcp.WithString("provider_namespace",
+			mcp.Description("provider의 namespace 입니다. 기본값은 'hashicorp' 입니다."),
+		),
+		mcp.WithString("provider_name",
+			mcp.Description("provider의 name 입니다. 예: 'aws', 'azurerm'. 'app.terraform.io/acme/aws' 처럼 전체 source 주소를 입력하면 해당 registry host를 사용합니다."),
+			mcp.Required(),
+		),
+		mcp.WithString("provider_version",
+			mcp.Description("확인할 대상 provider version 또는 version constraint 입니다. 최신버전은 생략하거나 공백을 입력합니다."),
+		),
+		mcp.WithString("from_version",
+			mcp.Description("현재 사용 중인 provider version 또는 version constraint 입니다. 입력하면 그 사이에 삭제된 block, argument, attribute도 알려줍니다."),
+		),
+		mcp.WithArray("resources",
+			mcp.Description("확인할 resource type 목록 입니다. 예: ['aws_s3_bucket', 'aws_instance']."),
+			mcp.WithStringItems(),
+		),
+		mcp.WithArray("data_sources",
+			mcp.Description("확인할 data source type 목록 입니다. 예: ['aws_ami']."),
+			mcp.WithStringItems(),
+		),
+	), tools.CheckDeprecations)
+
+	s.AddTool(mcp.NewTool("load_provider_schema",
+		mcp.WithDescription("'terraform providers schema -json' 결과를 불러옵니다. 불러온 schema는 get_provider_schema에서 사용합니다."),
+		mcp.WithString("path",
+			mcp.Description("'terraform providers schema -json' 결과 파일의 경로 입니다. 서버에 설정된 local root(--local-root) 안의 파일만 읽을 수 있습니다."),
+		),
+		mcp.WithString("json",
+			mcp.Description("'terraform providers schema -json' 결과 JSON 문자열 입니다. path 대신 사용합니다."),
+		),
+	), tools.LoadProviderSchema)
+
+	s.AddTool(mcp.NewTool("get_provider_schema",
+		mcp.WithDescription("불러온 provider schema에서 attribute type, nesting mode, sensitive, deprecated 여부를 정확하게 조회합니다. schema에 없는 block은 registry 문서를 분석한 결과를 반환합니다."),
+		mcp.WithString("provider_namespace",
+			mcp.Description("provider의 namespace 입니다. 기본값은 'hashicorp' 입니다."),
+		),
+		mcp.WithString("provider_name",
+			mcp.Description("provider의 name 입니다. 예: 'aws', 'azurerm'. 'app.terraform.io/acme/aws' 처럼 전체 source 주소를 입력하면 해당 registry host를 사용합니다."),
+			mcp.Required(),
+		),
+		mcp.WithString("provider_version",
+			mcp.Description("registry 문서로 대체할 때 사용할 provider version 또는 version constraint 입니다. 최신버전은 생략하거나 공백을 입력합니다."),
+		),
+		mcp.WithString("block_type",
+			mcp.Description("block 종류 입니다. 기본값은 'resource' 입니다. 'provider'는 provider 설정 block을 조회합니다."),
+			mcp.Enum("provider", "resource", "data", "ephemeral", "list"),
+		),
+		mcp.WithString("block_name",
+			mcp.Description("block의 type name 입니다. 예: 'aws_s3_bucket', 's3_bucket'. block_type이 'provider'이면 생략합니다."),
+		),
+		mcp.WithString("path",
+			mcp.Description("block 안의 attribute 또는 nested block 경로 입니다. 예: 'lifecycle_rule.expiration.days'. 생략하면 block 전체를 반환합니다."),
+		),
+	), tools.GetProviderSchema)
+
+	s.AddTool(mcp.NewTool("validate_hcl",
+		mcp.WithDescription("HCL snippet을 provider schema로 검증합니다. 알 수 없는 block type, 알 수 없거나 누락된 필수 argument, 잘못된 nested block, literal 값의 type 오류를 반환합니다. 불러온 provider schema가 없으면 registry 문서를 분석해 사용합니다."),
+		mcp.WithString("hcl",
+			mcp.Description("검증할 HCL 입니다. provider, resource, data, ephemeral block을 검증합니다."),
+			mcp.Required(),
+		),
+		mcp.WithString("provider_namespace",
+			mcp.Description("provider의 namespace 입니다. 기본값은 'hashicorp' 입니다."),
+		),
+		mcp.WithString("provider_name",
+			mcp.Description("모든 block에 사용할 provider의 name 입니다. 생략하면 type name의 prefix를 사용합니다. 예: 'aws_s3_bucket' -> 'aws'."),
+		),
+		mcp.WithString("provider_version",
+			mcp.Description("registry 문서로 대체할 때 사용할 provider version 또는 version constraint 입니다. 최신버전은 생략하거나 공백을 입력합니다."),
+		),
+	), tools.ValidateHCL)
+
 	s.AddTool(mcp.NewTool("get_module",
-		mcp.WithDescription("Git 저장소(GitLab/GitHub)에서 Terraform 모듈 정보를 가져옵니다."),
+		mcp.WithDescription("Terraform module source 주소(registry, Git, HTTP(S)/S3/GCS archive)에서 Terraform 모듈 정보를 가져옵니다."),
 		mcp.WithString("url",
-			mcp.Description("Git 저장소 URL입니다. SSH 또는 HTTPS 형식 모두 지원합니다."),
+			mcp.Description("Terraform module source 주소입니다. 예: 'terraform-aws-modules/vpc/aws', 'github.com/org/repo//modules/x?ref=v1.0.0', 'git@github.com:org/repo.git', 'git::https://example.com/repo.git', 'https://example.com/module.zip', 's3::https://...'. self-hosted Git 저장소는 'git::' 접두사나 '.git' 접미사를 사용합니다. 둘 다 없는 https 주소는 X-Terraform-Get이 없으면 Git으로 clone을 시도합니다."),
 			mcp.Required(),
 		),
+		mcp.WithString("ref",
+			mcp.Description("git source에서 사용할 branch, tag 또는 commit SHA 입니다. url의 '?ref='보다 우선합니다. 기본값은 저장소의 기본 branch 입니다."),
+		),
 		mcp.WithString("branch",
-			mcp.Description("사용할 브랜치 또는 태그입니다. 기본값은 main/master 브랜치입니다."),
+			mcp.Description("ref의 이전 이름입니다. ref와 같이 branch, tag 또는 commit SHA를 입력합니다."),
 		),
 		mcp.WithString("subdir",
 			mcp.Description("저장소 내 하위 디렉토리 경로입니다. 루트가 아닌 위치에 모듈이 있는 경우 사용합니다."),
 		),
+		mcp.WithString("version",
+			mcp.Description("registry module의 version 또는 version constraint 입니다. 최신버전은 생략하거나 공백을 입력합니다."),
+		),
+		withModuleView(),
 	), tools.GetModule)
 
+	s.AddTool(mcp.NewTool("get_local_module",
+		mcp.WithDescription("서버에 설정된 local root(--local-root) 안의 디렉토리에서 Terraform 모듈 정보를 get_module과 같은 형식으로 가져옵니다."),
+		mcp.WithString("path",
+			mcp.Description("모듈 디렉토리 경로입니다. 절대 경로 또는 local root 기준 상대 경로를 입력합니다. 예: 'modules/vpc'."),
+			mcp.Required(),
+		),
+		withModuleView(),
+	), tools.GetLocalModule)
+
+	s.AddTool(mcp.NewTool("search_modules",
+		mcp.WithDescription("Terraform registry에서 module을 검색합니다. query를 생략하면 namespace, provider 조건에 맞는 module 목록을 반환합니다."),
+		mcp.WithString("query",
+			mcp.Description("검색어 입니다. 예: 'vpc', 'eks'."),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("module의 namespace 입니다. 예: 'terraform-aws-modules'."),
+		),
+		mcp.WithString("provider",
+			mcp.Description("module의 provider(target system) 입니다. 예: 'aws', 'azurerm'."),
+		),
+		mcp.WithBoolean("verified",
+			mcp.Description("true 이면 verified module만 반환합니다."),
+		),
+		mcp.WithNumber("offset",
+			mcp.Description("건너뛸 결과 수 입니다. 이전 결과의 next_offset을 입력합니다."),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("반환할 최대 결과 수 입니다. 기본값은 10 입니다."),
+		),
+		mcp.WithString("host",
+			mcp.Description("검색할 registry host 입니다. 기본값은 설정된 기본 registry 입니다."),
+		),
+	), tools.SearchModules)
+
+	s.AddTool(mcp.NewTool("list_module_versions",
+		mcp.WithDescription("registry module의 버전 목록을 가져오고, version constraint에 맞는 버전을 찾습니다."),
+		mcp.WithString("module",
+			mcp.Description("module 주소 입니다. 예: 'terraform-aws-modules/vpc/aws'. 'app.terraform.io/acme/network/aws' 처럼 host를 포함하면 해당 registry를 사용합니다."),
+			mcp.Required(),
+		),
+		mcp.WithString("constraint",
+			mcp.Description("Terraform version constraint 입니다. 예: '~> 5.0'. 입력하면 조건에 맞는 버전만 반환하고 가장 높은 버전을 'resolved'로 알려줍니다."),
+		),
+	), tools.ListModuleVersions)
+
+	s.AddTool(mcp.NewTool("get_registry_module",
+		mcp.WithDescription("registry module의 input, output, dependency, resource, submodule, example 정보를 get_module과 같은 형식으로 가져옵니다."),
+		mcp.WithString("module",
+			mcp.Description("module 주소 입니다. 예: 'terraform-aws-modules/vpc/aws'. 'app.terraform.io/acme/network/aws' 처럼 host를 포함하면 해당 registry를 사용합니다."),
+			mcp.Required(),
+		),
+		mcp.WithString("version",
+			mcp.Description("module version 또는 version constraint 입니다. 최신버전은 생략하거나 공백을 입력합니다."),
+		),
+	), tools.GetRegistryModule)
+
 	return s
 }
 
+// withDocumentView adds the table of contents, section and paging parameters shared by the doc tools
+func withDocumentView() mcp.ToolOption {
+	options := []mcp.ToolOption{
+		mcp.WithBoolean("toc",
+			mcp.Description("true 이면 문서 대신 목차(section title, level, bytes)를 반환합니다. 큰 문서는 목차를 먼저 확인한 뒤 sections로 필요한 부분만 가져옵니다."),
+		),
+		mcp.WithArray("sections",
+			mcp.Description("가져올 section title 목록 입니다. 예: ['Argument Reference', 'Import', 'object_lock_configuration']. nested block 이름도 사용할 수 있습니다."),
+			mcp.WithStringItems(),
+		),
+		mcp.WithNumber("max_bytes",
+			mcp.Description("한 번에 반환할 최대 byte 수 입니다. 남은 내용이 있으면 cursor를 함께 반환합니다. 생략하면 전체를 반환합니다."),
+		),
+		mcp.WithString("cursor",
+			mcp.Description("이전 응답에서 받은 cursor 입니다. 같은 인자와 함께 입력하면 이어지는 내용을 반환합니다."),
+		),
+	}
+
+	return func(t *mcp.Tool) {
+		for _, option := range options {
+			option(t)
+		}
+	}
+}
+
+// withModuleView adds the parse mode and section parameters shared by the module tools
+func withModuleView() mcp.ToolOption {
+	options := []mcp.ToolOption{
+		mcp.WithBoolean("detail",
+			mcp.Description("true 이면 variable, output 외에 resource, data source, module call, provider, locals 블록도 분석합니다. 기본값은 false 입니다."),
+		),
+		mcp.WithArray("sections",
+			mcp.Description("반환할 section 목록 입니다. 'variables', 'outputs', 'providers', 'resources', 'data_sources', 'module_calls', 'locals' 중에서 선택합니다. 예: ['variables']. 생략하면 분석한 전체를 반환합니다. resources, data_sources, module_calls, locals를 선택하면 detail이 적용됩니다."),
+			mcp.WithStringItems(),
+		),
+	}
+
+	return func(t *mcp.Tool) {
+		for _, option := range options {
+			option(t)
+		}
+	}
+}
+
 // RunHttp starts the MCP server over HTTP
 func RunHttp(port uint16) error {
 	s := createMCPServer()
@@ -85,11 +457,10 @@ func RunHttp(port uint16) error {
 	return nil
 }
 
-// RunStdio starts the MCP server over stdio
-func RunStdio() error {
+// RunStdio starts the MCP server over stdio until ctx is done
+func RunStdio(ctx context.Context) error {
 	s := createMCPServer()
 	stdioServer := server.NewStdioServer(s)
-	ctx := context.Background()
 
 	if err := stdioServer.Listen(ctx, os.Stdin, os.Stdout); err != nil {
 		return err
@@ -4,69 +4,96 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"net/url"
+	"path"
 	"strings"
 
-	"github.com/Yunsang-Jeong/terraform-config-parser/pkg/parser"
-	"github.com/Yunsang-Jeong/terraform-config-parser/pkg/source"
+	"github.com/Yunsang-Jeong/terraform-mcp-server/pkg/utils/localdir"
+	"github.com/Yunsang-Jeong/terraform-mcp-server/pkg/utils/modconfig"
+	"github.com/Yunsang-Jeong/terraform-mcp-server/pkg/utils/modsource"
+
+	"github.com/Yunsang-Jeong/terraform-config-parser/pkg/filesystem"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
-// GetModule retrieves terraform module information from Git repositories (GitLab/GitHub)
-// instead of HashiCorp's public registry
+// GetModule retrieves terraform module information from any Terraform module
+// source address: registry modules, git repositories, HTTP(S), S3 and GCS archives
 func GetModule(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	// Extract module URL and optional parameters
+	// Extract module source and optional parameters
 	moduleURL, err := request.RequireString("url")
 	if err != nil {
 		return mcp.NewToolResultError("'url' parameter is required"), nil
 	}
 
 	// Optional parameters
+	// "branch" is the former name of "ref"; both accept a branch, tag or commit SHA
 	ref := request.GetString("ref", "")
+	if branch := request.GetString("branch", ""); branch != "" {
+		if ref != "" && ref != branch {
+			return mcp.NewToolResultError("'ref' and 'branch' name different refs; use 'ref' only"), nil
+		}
+		ref = branch
+	}
 	subDir := request.GetString("subdir", "")
+	version := request.GetString("version", "")
+	if _, err := modconfig.CheckSections(request.GetStringSlice("sections", nil)); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 
-	// Validate and normalize Git URL
-	gitURL, err := normalizeGitURL(moduleURL)
+	// Resolve the source address the way Terraform does
+	src, err := modsource.Parse(moduleURL)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Invalid Git URL: %v", err)), nil
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid module source: %v", err)), nil
 	}
-
-	// Create Git source
-	config := source.SourceConfig{
-		Ref:    ref,
-		SubDir: subDir,
+	if ref != "" {
+		// A ref names a git repository: self-hosted servers without ".git" are cloned as before
+		if src.Getter == modsource.GETTER_HTTP && src.Archive == "" {
+			src.Getter = modsource.GETTER_GIT
+		}
+		if src.Getter != modsource.GETTER_GIT {
+			return mcp.NewToolResultError(fmt.Sprintf("'ref' only applies to git sources, not %s sources", src.Getter)), nil
+		}
+		src.Ref = ref
 	}
-	gitSource := source.NewGitSource(gitURL, config)
-
-	// Fetch repository
-	fs, rootPath, err := gitSource.Fetch()
-	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Error fetching repository: %v", err)), nil
+	if version != "" && src.Getter != modsource.GETTER_REGISTRY {
+		return mcp.NewToolResultError(fmt.Sprintf("'version' only applies to registry sources, not %s sources", src.Getter)), nil
+	}
+	if subDir != "" {
+		src.Subdir = path.Join(src.Subdir, strings.Trim(subDir, "/"))
+		if src.Subdir == ".." || strings.HasPrefix(src.Subdir, "../") {
+			return mcp.NewToolResultError("'subdir' must not leave the module source"), nil
+		}
 	}
 
-	// Cleanup when done
-	defer gitSource.Cleanup()
-
-	// Parse Terraform configuration
-	terraformParser := parser.NewParser(fs, parser.Simple)
-	tfConfig, err := terraformParser.ParseTerraformWorkspace(rootPath)
+	// Fetch the module, aborting the download when the request is cancelled
+	module, err := modsource.Fetch(ctx, src, version)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Error parsing Terraform configuration: %v", err)), nil
+		return mcp.NewToolResultError(fmt.Sprintf("Error fetching module: %v", err)), nil
 	}
 
-	// Generate summary
-	summary, err := tfConfig.Summary(true)
-	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Error generating summary: %v", err)), nil
+	// Parse Terraform configuration
+	summary, errResult := parseModuleConfig(request, module.FS, module.Root)
+	if errResult != nil {
+		return errResult, nil
 	}
 
 	// Create response with module information
 	moduleInfo := map[string]interface{}{
-		"url":    gitURL,
-		"ref":    ref,
-		"subdir": subDir,
+		"source": src.String(),
+		"getter": module.Source.Getter,
+		"url":    module.Source.URL,
+		"ref":    module.Source.Ref,
+		"subdir": module.Source.Subdir,
 		"config": json.RawMessage(summary),
 	}
+	if module.Ref.Commit != "" {
+		moduleInfo["ref"] = module.Ref.Name
+		moduleInfo["ref_type"] = module.Ref.Kind
+		moduleInfo["commit"] = module.Ref.Commit
+	}
+	if module.Registry != "" {
+		moduleInfo["module"] = module.Registry
+		moduleInfo["version"] = module.Version
+	}
 
 	moduleInfoJSON, err := json.MarshalIndent(moduleInfo, "", "  ")
 	if err != nil {
@@ -76,41 +103,58 @@ func GetModule(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolR
 	return mcp.NewToolResultText(string(moduleInfoJSON)), nil
 }
 
-// normalizeGitURL converts various Git URL formats to a standardized format
-func normalizeGitURL(rawURL string) (string, error) {
-	// Handle different URL formats
-	if strings.HasPrefix(rawURL, "git@") {
-		// SSH format: git@github.com:user/repo.git -> https://github.com/user/repo.git
-		parts := strings.Split(rawURL, ":")
-		if len(parts) != 2 {
-			return "", fmt.Errorf("invalid SSH URL format")
-		}
-		host := strings.TrimPrefix(parts[0], "git@")
-		path := parts[1]
+// GetLocalModule retrieves terraform module information from a directory
+// inside the local roots the server was started with
+func GetLocalModule(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	localPath, err := request.RequireString("path")
+	if err != nil {
+		return mcp.NewToolResultError("'path' parameter is required"), nil
+	}
 
-		// Ensure .git suffix for SSH URLs
-		if !strings.HasSuffix(path, ".git") {
-			path = path + ".git"
-		}
+	dir, err := localdir.Resolve(localPath)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid local path: %v", err)), nil
+	}
+
+	// Parse Terraform configuration
+	summary, errResult := parseModuleConfig(request, localdir.NewFS(dir.Root), dir.Path)
+	if errResult != nil {
+		return errResult, nil
+	}
 
-		return fmt.Sprintf("https://%s/%s", host, path), nil
+	moduleInfo := map[string]interface{}{
+		"root":   dir.Root,
+		"path":   dir.Rel,
+		"config": json.RawMessage(summary),
 	}
 
-	// Parse as URL
-	u, err := url.Parse(rawURL)
+	moduleInfoJSON, err := json.MarshalIndent(moduleInfo, "", "  ")
 	if err != nil {
-		return "", fmt.Errorf("failed to parse URL: %w", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Error marshaling module info: %v", err)), nil
 	}
 
-	// Ensure HTTPS scheme
-	if u.Scheme == "" || u.Scheme == "http" {
-		u.Scheme = "https"
+	return mcp.NewToolResultText(string(moduleInfoJSON)), nil
+}
+
+// parseModuleConfig parses the module in dir as the "detail" and "sections"
+// parameters ask. Sections that only a detailed parse reads imply "detail".
+func parseModuleConfig(request mcp.CallToolRequest, fs filesystem.FileReader, dir string) (json.RawMessage, *mcp.CallToolResult) {
+	sections := request.GetStringSlice("sections", nil)
+	detailed, err := modconfig.CheckSections(sections)
+	if err != nil {
+		return nil, mcp.NewToolResultError(err.Error())
 	}
+	detailed = detailed || request.GetBool("detail", false)
 
-	// Ensure .git suffix for proper Git operations
-	if !strings.HasSuffix(u.Path, ".git") {
-		u.Path = u.Path + ".git"
+	config, err := modconfig.Parse(fs, dir, detailed)
+	if err != nil {
+		return nil, mcp.NewToolResultError(fmt.Sprintf("Error parsing Terraform configuration: %v", err))
+	}
+
+	summary, err := json.Marshal(config.Select(sections))
+	if err != nil {
+		return nil, mcp.NewToolResultError(fmt.Sprintf("Error generating summary: %v", err))
 	}
 
-	return u.String(), nil
+	return summary, nil
 }
@@ -2,97 +2,16 @@ package tools
 
 import (
 	"context"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/Yunsang-Jeong/terraform-mcp-server/pkg/utils/localdir"
+
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
-func TestNormalizeGitURL(t *testing.T) {
-	tests := []struct {
-		name     string
-		input    string
-		expected string
-		wantErr  bool
-	}{
-		{
-			name:     "SSH URL with git@ prefix",
-			input:    "git@github.com:user/repo.git",
-			expected: "https://github.com/user/repo.git",
-			wantErr:  false,
-		},
-		{
-			name:     "SSH URL without .git suffix",
-			input:    "git@github.com:user/repo",
-			expected: "https://github.com/user/repo.git",
-			wantErr:  false,
-		},
-		{
-			name:     "HTTPS URL with .git suffix",
-			input:    "https://github.com/user/repo.git",
-			expected: "https://github.com/user/repo.git",
-			wantErr:  false,
-		},
-		{
-			name:     "HTTPS URL without .git suffix",
-			input:    "https://github.com/user/repo",
-			expected: "https://github.com/user/repo.git",
-			wantErr:  false,
-		},
-		{
-			name:     "HTTP URL converted to HTTPS",
-			input:    "http://github.com/user/repo",
-			expected: "https://github.com/user/repo.git",
-			wantErr:  false,
-		},
-		{
-			name:     "URL without scheme",
-			input:    "github.com/user/repo",
-			expected: "https://github.com/user/repo.git",
-			wantErr:  false,
-		},
-		{
-			name:     "GitLab URL",
-			input:    "https://gitlab.com/user/repo.git",
-			expected: "https://gitlab.com/user/repo.git",
-			wantErr:  false,
-		},
-		{
-			name:     "Invalid SSH format",
-			input:    "git@github.com",
-			expected: "",
-			wantErr:  true,
-		},
-		{
-			name:     "Invalid URL format",
-			input:    "not-a-valid-url",
-			expected: "https://not-a-valid-url.git",
-			wantErr:  false,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result, err := normalizeGitURL(tt.input)
-			
-			if tt.wantErr {
-				if err == nil {
-					t.Errorf("normalizeGitURL() expected error but got none")
-				}
-				return
-			}
-			
-			if err != nil {
-				t.Errorf("normalizeGitURL() unexpected error: %v", err)
-				return
-			}
-			
-			if result != tt.expected {
-				t.Errorf("normalizeGitURL() = %v, want %v", result, tt.expected)
-			}
-		})
-	}
-}
-
 func TestGetModule_MissingURL(t *testing.T) {
 	ctx := context.Background()
 	
@@ -211,14 +130,6 @@ func TestGetModule_WithSubdirectory(t *testing.T) {
 }
 
 // Benchmark tests
-func BenchmarkNormalizeGitURL(b *testing.B) {
-	testURL := "git@github.com:user/repo"
-	
-	for i := 0; i < b.N; i++ {
-		_, _ = normalizeGitURL(testURL)
-	}
-}
-
 func BenchmarkGetModule_ParameterExtraction(b *testing.B) {
 	ctx := context.Background()
 	request := mcp.CallToolRequest{
@@ -236,3 +147,130 @@ func BenchmarkGetModule_ParameterExtraction(b *testing.B) {
 		_, _ = GetModule(ctx, request)
 	}
 }
+
+func TestGetModule_RefValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		arguments map[string]interface{}
+		want      string
+	}{
+		{
+			name:      "ref and branch differ",
+			arguments: map[string]interface{}{"url": "github.com/user/repo", "ref": "v1.0.0", "branch": "main"},
+			want:      "'ref' and 'branch' name different refs",
+		},
+		{
+			name:      "ref on a registry source",
+			arguments: map[string]interface{}{"url": "terraform-aws-modules/vpc/aws", "ref": "v5.0.0"},
+			want:      "'ref' only applies to git sources",
+		},
+		{
+			name:      "ref on an archive with a query",
+			arguments: map[string]interface{}{"url": "https://example.com/module?archive=zip", "ref": "main"},
+			want:      "'ref' only applies to git sources",
+		},
+		{
+			name:      "ref on an archive",
+			arguments: map[string]interface{}{"url": "https://example.com/module.zip", "branch": "main"},
+			want:      "'ref' only applies to git sources",
+		},
+		{
+			name:      "version on a git source",
+			arguments: map[string]interface{}{"url": "github.com/user/repo", "version": "~> 1.0"},
+			want:      "'version' only applies to registry sources",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: tt.arguments}}
+
+			result, err := GetModule(context.Background(), request)
+			if err != nil {
+				t.Fatalf("GetModule() unexpected error: %v", err)
+			}
+			if !result.IsError || !strings.Contains(result.Content[0].(mcp.TextContent).Text, tt.want) {
+				t.Errorf("GetModule() = %+v, want error containing %q", result.Content, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetLocalModule(t *testing.T) {
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, "modules", "vpc"), 0o755)
+	os.WriteFile(filepath.Join(root, "modules", "vpc", "variables.tf"), []byte("variable \"cidr\" {\n  type = string\n}\n"), 0o644)
+	if err := localdir.Configure([]string{root}); err != nil {
+		t.Fatalf("localdir.Configure() unexpected error: %v", err)
+	}
+	t.Cleanup(func() { localdir.Configure(nil) })
+
+	tests := []struct {
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{path: "modules/vpc", want: `"cidr"`},
+		{path: "../", wantErr: true},
+		{path: "/etc", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		request := mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{"path": tt.path}}}
+
+		result, err := GetLocalModule(context.Background(), request)
+		if err != nil {
+			t.Fatalf("GetLocalModule() unexpected error: %v", err)
+		}
+		text := result.Content[0].(mcp.TextContent).Text
+		if result.IsError != tt.wantErr || !strings.Contains(text, tt.want) {
+			t.Errorf("GetLocalModule(%q) = %s, want error %v containing %s", tt.path, text, tt.wantErr, tt.want)
+		}
+	}
+}
+
+func TestGetLocalModule_Sections(t *testing.T) {
+	root := t.TempDir()
+	os.WriteFile(filepath.Join(root, "main.tf"), []byte("variable \"cidr\" {}\n\nresource \"aws_vpc\" \"this\" {}\n\noutput \"id\" {\n  value = aws_vpc.this.id\n}\n"), 0o644)
+	if err := localdir.Configure([]string{root}); err != nil {
+		t.Fatalf("localdir.Configure() unexpected error: %v", err)
+	}
+	t.Cleanup(func() { localdir.Configure(nil) })
+
+	tests := []struct {
+		arguments map[string]interface{}
+		want      []string
+		notWant   []string
+		wantErr   bool
+	}{
+		{arguments: map[string]interface{}{}, want: []string{`"variables"`, `"outputs"`}, notWant: []string{`"resources"`}},
+		{arguments: map[string]interface{}{"detail": true}, want: []string{`"variables"`, `"resources"`, `"aws_vpc"`}},
+		{arguments: map[string]interface{}{"sections": []interface{}{"variables"}}, want: []string{`"cidr"`}, notWant: []string{`"outputs"`}},
+		{arguments: map[string]interface{}{"sections": []interface{}{"resources"}}, want: []string{`"aws_vpc"`}, notWant: []string{`"variables"`}},
+		{arguments: map[string]interface{}{"sections": []interface{}{"resource"}}, want: []string{`did you mean`}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt.arguments["path"] = "."
+		request := mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: tt.arguments}}
+
+		result, err := GetLocalModule(context.Background(), request)
+		if err != nil {
+			t.Fatalf("GetLocalModule() unexpected error: %v", err)
+		}
+		text := result.Content[0].(mcp.TextContent).Text
+		if result.IsError != tt.wantErr {
+			t.Errorf("GetLocalModule(%v) = %s, want error %v", tt.arguments, text, tt.wantErr)
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(text, want) {
+				t.Errorf("GetLocalModule(%v) = %s, want it to contain %s", tt.arguments, text, want)
+			}
+		}
+		for _, notWant := range tt.notWant {
+			if strings.Contains(text, notWant) {
+				t.Errorf("GetLocalModule(%v) = %s, want it not to contain %s", tt.arguments, text, notWant)
+			}
+		}
+	}
+}
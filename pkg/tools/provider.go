@@ -2,40 +2,114 @@ package tools
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
 
+	"github.com/Yunsang-Jeong/terraform-mcp-server/pkg/utils"
+	"github.com/Yunsang-Jeong/terraform-mcp-server/pkg/utils/docschema"
+	"github.com/Yunsang-Jeong/terraform-mcp-server/pkg/utils/docsearch"
 	"github.com/Yunsang-Jeong/terraform-mcp-server/pkg/utils/registry"
+	"github.com/Yunsang-Jeong/terraform-mcp-server/pkg/utils/textdiff"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"golang.org/x/sync/errgroup"
 )
 
-func getBlockDocument(providerNamespace, providerName, providerVersion, blockType, blockName string) (string, error) {
+const (
+	OUTPUT_MARKDOWN = "markdown"
+	OUTPUT_SCHEMA   = "schema"
+)
+
+// docNotFoundError is returned when no document matches a block name, listing close matches
+type docNotFoundError struct {
+	category    string
+	name        string
+	suggestions []docsearch.Match
+}
+
+func newDocNotFoundError(provider registry.ProviderAddress, category, name string, docs []registry.ProviderDoc) *docNotFoundError {
+	return &docNotFoundError{
+		category:    category,
+		name:        name,
+		suggestions: docsearch.Search(docs, provider.Name, name, docsearch.MIN_SUGGESTION_SCORE, 5),
+	}
+}
+
+func (e *docNotFoundError) Error() string {
+	msg := fmt.Sprintf("document not found: %s/%s", e.category, e.name)
+
+	if len(e.suggestions) > 0 {
+		slugs := []string{}
+		for _, s := range e.suggestions {
+			slugs = append(slugs, s.Slug)
+		}
+		msg += fmt.Sprintf(". did you mean: %s?", strings.Join(slugs, ", "))
+	}
+
+	return msg
+}
+
+// findProviderDoc looks name up among docs, suggesting close matches when it is missing
+func findProviderDoc(provider registry.ProviderAddress, docs []registry.ProviderDoc, category, name string) (registry.ProviderDoc, error) {
+	doc, ok := docsearch.Find(docs, provider.Name, name)
+	if !ok {
+		return doc, newDocNotFoundError(provider, category, name, docs)
+	}
+
+	return doc, nil
+}
+
+func getBlockDocument(ctx context.Context, provider registry.ProviderAddress, providerVersion, blockType, blockName string) (string, error) {
 	var docId string
 
+	client, err := provider.Client()
+	if err != nil {
+		return "", err
+	}
+
 	if providerVersion == "" {
-		provider, err := registry.GetProvider(providerNamespace, providerName)
+		_, docs, err := client.LatestProviderDocs(ctx, provider.Namespace, provider.Name)
 		if err != nil {
 			return "", err
 		}
 
-		for _, doc := range provider.Docs {
-			if doc.Language == "hcl" && doc.Category == blockType && doc.Slug == blockName {
-				docId = doc.ID
-				break
-			}
+		doc, err := findProviderDoc(provider, filterDocsByCategory(docs, blockType), blockType, blockName)
+		if err != nil {
+			return "", err
 		}
+		docId = doc.ID
 	} else {
-		versionId, err := registry.GetProviderVersionId(providerNamespace, providerName, providerVersion)
+		// provider_version may be an exact version or a constraint such as "~> 5.0"
+		resolved, err := client.ResolveProviderVersion(ctx, provider.Namespace, provider.Name, providerVersion)
 		if err != nil {
 			return "", err
 		}
 
-		docId, err = registry.GetProviderDocsId(versionId, blockType, blockName)
+		versionId, err := client.GetProviderVersionId(ctx, provider.Namespace, provider.Name, resolved.Version)
 		if err != nil {
 			return "", err
 		}
+
+		// Listing the whole category is only needed when the name is not an exact slug
+		docId, err = client.GetProviderDocsId(ctx, versionId, blockType, blockName)
+		if err != nil {
+			docs, listErr := client.ListProviderDocs(ctx, versionId, blockType)
+			if listErr != nil {
+				return "", err
+			}
+
+			doc, err := findProviderDoc(provider, docs, blockType, blockName)
+			if err != nil {
+				return "", err
+			}
+			docId = doc.ID
+		}
 	}
 
-	contents, err := registry.GetProviderDocsContent(docId)
+	contents, err := client.GetProviderDocsContent(ctx, docId)
 	if err != nil {
 		return "", err
 	}
@@ -43,6 +117,78 @@ func getBlockDocument(providerNamespace, providerName, providerVersion, blockTyp
 	return contents, nil
 }
 
+// documentView selects what part of a markdown document a doc tool returns
+type documentView struct {
+	toc      bool
+	sections []string
+	maxBytes int
+	cursor   string
+}
+
+func documentViewFrom(request mcp.CallToolRequest) documentView {
+	return documentView{
+		toc:      request.GetBool("toc", false),
+		sections: request.GetStringSlice("sections", nil),
+		maxBytes: request.GetInt("max_bytes", 0),
+		cursor:   request.GetString("cursor", ""),
+	}
+}
+
+// result returns the table of contents, or the requested sections one page at a time
+func (v documentView) result(contents string) *mcp.CallToolResult {
+	if v.toc {
+		tocInfo := map[string]interface{}{
+			"bytes":    len(contents),
+			"sections": docschema.Headings(contents),
+		}
+
+		tocInfoJSON, err := json.MarshalIndent(tocInfo, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Error marshaling table of contents: %v", err))
+		}
+
+		return mcp.NewToolResultText(string(tocInfoJSON))
+	}
+
+	if len(v.sections) > 0 {
+		selected, err := docschema.Select(contents, v.sections)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error())
+		}
+		contents = selected
+	}
+
+	page, err := docschema.Chunk(contents, v.maxBytes, v.cursor)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error())
+	}
+
+	if page.Cursor == "" {
+		return mcp.NewToolResultText(page.Content)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.NewTextContent(page.Content),
+			mcp.NewTextContent(fmt.Sprintf("[bytes %d-%d of %d returned; call again with cursor %q for the rest]", page.Start, page.End, page.Total, page.Cursor)),
+		},
+	}
+}
+
+// blockDocumentResult renders a block document as markdown through view or as its structured schema
+func blockDocumentResult(contents, output string, view documentView) *mcp.CallToolResult {
+	if output != OUTPUT_SCHEMA {
+		return view.result(contents)
+	}
+
+	schemaJSON, err := json.MarshalIndent(docschema.Parse(contents), "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Error marshaling schema: %v", err))
+	}
+
+	return mcp.NewToolResultText(string(schemaJSON))
+}
+
 func GetResourceBlockDocument(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	providerNamespace := request.GetString("provider_namespace", "hashicorp")
 
@@ -60,12 +206,22 @@ func GetResourceBlockDocument(ctx context.Context, request mcp.CallToolRequest)
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	contents, err := getBlockDocument(providerNamespace, providerName, providerVersion, blockType, blockName)
+	output := request.GetString("output", OUTPUT_MARKDOWN)
+	if !utils.IsInList(output, []string{OUTPUT_MARKDOWN, OUTPUT_SCHEMA}) {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid output: %s", output)), nil
+	}
+
+	provider, err := registry.ParseProviderAddress(providerName, providerNamespace)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	contents, err := getBlockDocument(ctx, provider, providerVersion, blockType, blockName)
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	return mcp.NewToolResultText(contents), nil
+	return blockDocumentResult(contents, output, documentViewFrom(request)), nil
 }
 
 func GetDataBlockDocument(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -85,10 +241,726 @@ func GetDataBlockDocument(ctx context.Context, request mcp.CallToolRequest) (*mc
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	contents, err := getBlockDocument(providerNamespace, providerName, providerVersion, blockType, blockName)
+	output := request.GetString("output", OUTPUT_MARKDOWN)
+	if !utils.IsInList(output, []string{OUTPUT_MARKDOWN, OUTPUT_SCHEMA}) {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid output: %s", output)), nil
+	}
+
+	provider, err := registry.ParseProviderAddress(providerName, providerNamespace)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	contents, err := getBlockDocument(ctx, provider, providerVersion, blockType, blockName)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	return blockDocumentResult(contents, output, documentViewFrom(request)), nil
+}
+
+func ListProviderVersions(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	providerNamespace := request.GetString("provider_namespace", "hashicorp")
+
+	providerName, err := request.RequireString("provider_name")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	constraint := request.GetString("constraint", "")
+
+	provider, err := registry.ParseProviderAddress(providerName, providerNamespace)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	client, err := provider.Client()
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	versions, err := client.ListProviderVersions(ctx, provider.Namespace, provider.Name)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	versionsInfo := map[string]interface{}{
+		"provider": provider.String(),
+	}
+
+	if constraint != "" {
+		matching, err := registry.MatchingVersions(versions, constraint)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if len(matching) == 0 {
+			return mcp.NewToolResultError(fmt.Sprintf("no version of %s matches %q", provider, constraint)), nil
+		}
+
+		versionsInfo["constraint"] = constraint
+		versionsInfo["resolved"] = matching[0].Version
+		versions = matching
+	} else if latest, err := registry.ResolveVersion(versions, ""); err == nil {
+		versionsInfo["latest"] = latest.Version
+	}
+
+	versionsInfo["versions"] = versions
+
+	versionsInfoJSON, err := json.MarshalIndent(versionsInfo, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Error marshaling versions: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(versionsInfoJSON)), nil
+}
+
+// getProviderDocs returns the documentation index of the provider version
+// matching providerVersion (latest when empty) and the concrete version it belongs to
+func getProviderDocs(ctx context.Context, client *registry.Client, provider registry.ProviderAddress, providerVersion, category string) (string, []registry.ProviderDoc, error) {
+	if providerVersion == "" {
+		version, docs, err := client.LatestProviderDocs(ctx, provider.Namespace, provider.Name)
+		if err != nil {
+			return "", nil, err
+		}
+
+		return version, filterDocsByCategory(docs, category), nil
+	}
+
+	resolved, err := client.ResolveProviderVersion(ctx, provider.Namespace, provider.Name, providerVersion)
+	if err != nil {
+		return "", nil, err
+	}
+
+	versionId, err := client.GetProviderVersionId(ctx, provider.Namespace, provider.Name, resolved.Version)
+	if err != nil {
+		return "", nil, err
+	}
+
+	docs, err := client.ListProviderDocs(ctx, versionId, category)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return resolved.Version, docs, nil
+}
+
+// getCategoryDocs is getProviderDocs for a single category, failing with the
+// categories the provider version does publish when it has none of category
+func getCategoryDocs(ctx context.Context, client *registry.Client, provider registry.ProviderAddress, providerVersion, category string) (string, []registry.ProviderDoc, error) {
+	version, docs, err := getProviderDocs(ctx, client, provider, providerVersion, category)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if len(docs) == 0 {
+		_, all, err := getProviderDocs(ctx, client, provider, providerVersion, "")
+		if err != nil {
+			return "", nil, err
+		}
+		return "", nil, fmt.Errorf("%s %s has no %q docs; available categories: %s", provider, version, category, strings.Join(registry.DocCategories(all), ", "))
+	}
+
+	return version, docs, nil
+}
+
+// getCategoryDocument fetches the document called name in category
+func getCategoryDocument(ctx context.Context, provider registry.ProviderAddress, providerVersion, category, name string) (string, error) {
+	client, err := provider.Client()
+	if err != nil {
+		return "", err
+	}
+
+	_, docs, err := getCategoryDocs(ctx, client, provider, providerVersion, category)
+	if err != nil {
+		return "", err
+	}
+
+	doc, err := findProviderDoc(provider, docs, category, name)
+	if err != nil {
+		return "", err
+	}
+
+	return client.GetProviderDocsContent(ctx, doc.ID)
+}
+
+// upgradeGuide picks the upgrade guide for the major version of version,
+// e.g. "version-5-upgrade" or "5.0-upgrade-guide" for 5.31.0
+func upgradeGuide(guides []registry.ProviderDoc, version string) (registry.ProviderDoc, bool) {
+	major, _, _ := strings.Cut(version, ".")
+
+	for _, guide := range guides {
+		slug := strings.ToLower(guide.Slug)
+		if !strings.Contains(slug, "upgrade") {
+			continue
+		}
+
+		numbers := strings.FieldsFunc(slug, func(r rune) bool { return !unicode.IsDigit(r) })
+		if len(numbers) > 0 && numbers[0] == major {
+			return guide, true
+		}
+	}
+
+	return registry.ProviderDoc{}, false
+}
+
+// filterDocsByCategory keeps the docs of one category; an empty category keeps all of them
+func filterDocsByCategory(docs []registry.ProviderDoc, category string) []registry.ProviderDoc {
+	if category == "" {
+		return docs
+	}
+
+	filtered := []registry.ProviderDoc{}
+	for _, doc := range docs {
+		if doc.Category == category {
+			filtered = append(filtered, doc)
+		}
+	}
+	return filtered
+}
+
+func ListProviderDocs(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	providerNamespace := request.GetString("provider_namespace", "hashicorp")
+
+	providerName, err := request.RequireString("provider_name")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	providerVersion := request.GetString("provider_version", "")
+	category := request.GetString("category", "")
+	subcategory := request.GetString("subcategory", "")
+
+	provider, err := registry.ParseProviderAddress(providerName, providerNamespace)
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	return mcp.NewToolResultText(contents), nil
+	client, err := provider.Client()
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	version, docs, err := getProviderDocs(ctx, client, provider, providerVersion, category)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	type docEntry struct {
+		Category    string `json:"category"`
+		Subcategory string `json:"subcategory,omitempty"`
+		Slug        string `json:"slug"`
+		Title       string `json:"title"`
+	}
+
+	entries := []docEntry{}
+	for _, doc := range docs {
+		if subcategory != "" && !strings.EqualFold(doc.Subcategory, subcategory) {
+			continue
+		}
+		entries = append(entries, docEntry{
+			Category:    doc.Category,
+			Subcategory: doc.Subcategory,
+			Slug:        doc.Slug,
+			Title:       doc.Title,
+		})
+	}
+
+	docsInfo := map[string]interface{}{
+		"provider": provider.String(),
+		"version":  version,
+		"count":    len(entries),
+		"docs":     entries,
+	}
+	if category != "" {
+		docsInfo["category"] = category
+	} else {
+		docsInfo["categories"] = registry.DocCategories(docs)
+	}
+	if subcategory != "" {
+		docsInfo["subcategory"] = subcategory
+	}
+
+	docsInfoJSON, err := json.MarshalIndent(docsInfo, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Error marshaling docs: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(docsInfoJSON)), nil
+}
+
+func SearchProviderDocs(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	providerNamespace := request.GetString("provider_namespace", "hashicorp")
+
+	providerName, err := request.RequireString("provider_name")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	query, err := request.RequireString("query")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	providerVersion := request.GetString("provider_version", "")
+	category := request.GetString("category", "")
+	limit := request.GetInt("limit", 10)
+
+	provider, err := registry.ParseProviderAddress(providerName, providerNamespace)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	client, err := provider.Client()
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	version, docs, err := getProviderDocs(ctx, client, provider, providerVersion, category)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	type matchEntry struct {
+		Category    string  `json:"category"`
+		Subcategory string  `json:"subcategory,omitempty"`
+		Slug        string  `json:"slug"`
+		Title       string  `json:"title"`
+		Score       float64 `json:"score"`
+	}
+
+	entries := []matchEntry{}
+	for _, match := range docsearch.Search(docs, provider.Name, query, docsearch.MIN_SUGGESTION_SCORE, limit) {
+		entries = append(entries, matchEntry{
+			Category:    match.Category,
+			Subcategory: match.Subcategory,
+			Slug:        match.Slug,
+			Title:       match.Title,
+			Score:       match.Score,
+		})
+	}
+
+	searchInfo := map[string]interface{}{
+		"provider": provider.String(),
+		"version":  version,
+		"query":    query,
+		"matches":  entries,
+	}
+
+	searchInfoJSON, err := json.MarshalIndent(searchInfo, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Error marshaling search results: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(searchInfoJSON)), nil
+}
+
+func GetProviderDocument(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	providerNamespace := request.GetString("provider_namespace", "hashicorp")
+
+	providerName, err := request.RequireString("provider_name")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	providerVersion := request.GetString("provider_version", "")
+
+	category, err := request.RequireString("category")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	slug, err := request.RequireString("slug")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	provider, err := registry.ParseProviderAddress(providerName, providerNamespace)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	contents, err := getCategoryDocument(ctx, provider, providerVersion, category, slug)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	return documentViewFrom(request).result(contents), nil
+}
+
+func GetProviderFunction(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	providerNamespace := request.GetString("provider_namespace", "hashicorp")
+
+	providerName, err := request.RequireString("provider_name")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	providerVersion := request.GetString("provider_version", "")
+
+	functionName, err := request.RequireString("function_name")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	provider, err := registry.ParseProviderAddress(providerName, providerNamespace)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	contents, err := getCategoryDocument(ctx, provider, providerVersion, "functions", functionName)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	return documentViewFrom(request).result(contents), nil
+}
+
+func GetProviderGuide(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	providerNamespace := request.GetString("provider_namespace", "hashicorp")
+
+	providerName, err := request.RequireString("provider_name")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	providerVersion := request.GetString("provider_version", "")
+	guideName := request.GetString("guide_name", "")
+
+	provider, err := registry.ParseProviderAddress(providerName, providerNamespace)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	client, err := provider.Client()
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	version, guides, err := getCategoryDocs(ctx, client, provider, providerVersion, "guides")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	var guide registry.ProviderDoc
+	if guideName != "" {
+		guide, err = findProviderDoc(provider, guides, "guides", guideName)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+	} else {
+		// Without a name, the upgrade guide into the requested major version is what is usually wanted
+		var ok bool
+		guide, ok = upgradeGuide(guides, version)
+		if !ok {
+			slugs := []string{}
+			for _, g := range guides {
+				slugs = append(slugs, g.Slug)
+			}
+			return mcp.NewToolResultError(fmt.Sprintf("no upgrade guide for %s %s; available guides: %s", provider, version, strings.Join(slugs, ", "))), nil
+		}
+	}
+
+	contents, err := client.GetProviderDocsContent(ctx, guide.ID)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	return documentViewFrom(request).result(contents), nil
+}
+
+func GetProviderExamples(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	providerNamespace := request.GetString("provider_namespace", "hashicorp")
+
+	providerName, err := request.RequireString("provider_name")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	providerVersion := request.GetString("provider_version", "")
+	category := request.GetString("category", "resources")
+
+	blockName, err := request.RequireString("block_name")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	provider, err := registry.ParseProviderAddress(providerName, providerNamespace)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	contents, err := getCategoryDocument(ctx, provider, providerVersion, category, blockName)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	examples := docschema.Examples(contents)
+
+	examplesInfo := map[string]interface{}{
+		"provider": provider.String(),
+		"category": category,
+		"name":     blockName,
+		"count":    len(examples),
+		"examples": examples,
+	}
+
+	examplesInfoJSON, err := json.MarshalIndent(examplesInfo, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Error marshaling examples: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(examplesInfoJSON)), nil
+}
+
+func DiffProviderDocument(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	providerNamespace := request.GetString("provider_namespace", "hashicorp")
+
+	providerName, err := request.RequireString("provider_name")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	category := request.GetString("category", "resources")
+
+	blockName, err := request.RequireString("block_name")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	fromVersion, err := request.RequireString("from_version")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	toVersion, err := request.RequireString("to_version")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	unifiedDiff := request.GetBool("unified_diff", true)
+	contextLines := request.GetInt("context", 3)
+	if contextLines < 0 {
+		return mcp.NewToolResultError("'context' must not be negative"), nil
+	}
+
+	provider, err := registry.ParseProviderAddress(providerName, providerNamespace)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	client, err := provider.Client()
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	// Both sides may be constraints such as "~> 3.0"; the diff is between the versions they resolve to
+	versions := [2]string{}
+	contents := [2]string{}
+	for i, constraint := range []string{fromVersion, toVersion} {
+		resolved, err := client.ResolveProviderVersion(ctx, provider.Namespace, provider.Name, constraint)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		versions[i] = resolved.Version
+
+		contents[i], err = getBlockDocument(ctx, provider, resolved.Version, category, blockName)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("%s %s: %v", provider, resolved.Version, err)), nil
+		}
+	}
+
+	changes := docschema.Compare(docschema.Parse(contents[0]), docschema.Parse(contents[1]))
+
+	summary := map[string]int{}
+	for _, change := range changes {
+		summary[change.Kind]++
+	}
+
+	diffInfo := map[string]interface{}{
+		"provider":     provider.String(),
+		"category":     category,
+		"name":         blockName,
+		"from_version": versions[0],
+		"to_version":   versions[1],
+		"summary":      summary,
+		"changes":      changes,
+	}
+	if unifiedDiff {
+		diffInfo["diff"] = textdiff.Unified(versions[0], versions[1], contents[0], contents[1], contextLines)
+	}
+
+	diffInfoJSON, err := json.MarshalIndent(diffInfo, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Error marshaling diff: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(diffInfoJSON)), nil
+}
+
+const (
+	BLOCK_STATUS_OK               = "ok"
+	BLOCK_STATUS_HAS_DEPRECATIONS = "has_deprecations"
+	BLOCK_STATUS_DEPRECATED       = "deprecated"
+	BLOCK_STATUS_REMOVED          = "removed"
+	BLOCK_STATUS_NOT_FOUND        = "not_found"
+	// BLOCK_STATUS_ERROR is a block whose doc could not be fetched, e.g. while the registry was failing
+	BLOCK_STATUS_ERROR = "error"
+
+	// DEPRECATION_CHECK_CONCURRENCY bounds the docs fetched at once by check_deprecations
+	DEPRECATION_CHECK_CONCURRENCY = 4
+)
+
+// blockCheck is the deprecation report of one resource or data source
+type blockCheck struct {
+	Category     string                  `json:"category"`
+	Name         string                  `json:"name"`
+	Status       string                  `json:"status"`
+	Notes        []string                `json:"notes,omitempty"`
+	Deprecations []docschema.Deprecation `json:"deprecations,omitempty"`
+	Removed      []docschema.Change      `json:"removed,omitempty"`
+	UpgradeGuide []string                `json:"upgrade_guide_sections,omitempty"`
+	Error        string                  `json:"error,omitempty"`
+}
+
+// checkBlock reports what the docs of version say is deprecated in a block and,
+// when fromVersion is set, what was removed since then
+func checkBlock(ctx context.Context, provider registry.ProviderAddress, version, fromVersion, category, name, guide string) blockCheck {
+	check := blockCheck{Category: category, Name: name, Status: BLOCK_STATUS_OK}
+
+	if guide != "" {
+		check.UpgradeGuide = docschema.Mentions(guide, provider.Name+"_"+docsearch.Normalize(provider.Name, name))
+	}
+
+	contents, err := getBlockDocument(ctx, provider, version, category, name)
+	if err != nil {
+		// Only a missing doc says the block is gone; any other failure says nothing about it
+		var notFound *docNotFoundError
+		if !errors.As(err, &notFound) {
+			check.Status, check.Error = BLOCK_STATUS_ERROR, err.Error()
+			return check
+		}
+
+		check.Status, check.Error = BLOCK_STATUS_NOT_FOUND, err.Error()
+		if fromVersion != "" {
+			if _, fromErr := getBlockDocument(ctx, provider, fromVersion, category, name); fromErr == nil {
+				check.Status, check.Error = BLOCK_STATUS_REMOVED, ""
+			}
+		}
+		return check
+	}
+
+	check.Notes, check.Deprecations = docschema.Deprecations(contents)
+
+	if fromVersion != "" {
+		if fromContents, err := getBlockDocument(ctx, provider, fromVersion, category, name); err == nil {
+			for _, change := range docschema.Compare(docschema.Parse(fromContents), docschema.Parse(contents)) {
+				if change.Kind == docschema.CHANGE_REMOVED {
+					check.Removed = append(check.Removed, change)
+				}
+			}
+		}
+	}
+
+	switch {
+	case len(check.Notes) > 0:
+		check.Status = BLOCK_STATUS_DEPRECATED
+	case len(check.Deprecations) > 0 || len(check.Removed) > 0:
+		check.Status = BLOCK_STATUS_HAS_DEPRECATIONS
+	}
+
+	return check
+}
+
+func CheckDeprecations(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	providerNamespace := request.GetString("provider_namespace", "hashicorp")
+
+	providerName, err := request.RequireString("provider_name")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	providerVersion := request.GetString("provider_version", "")
+	fromVersion := request.GetString("from_version", "")
+	resources := request.GetStringSlice("resources", nil)
+	dataSources := request.GetStringSlice("data_sources", nil)
+
+	if len(resources) == 0 && len(dataSources) == 0 {
+		return mcp.NewToolResultError("at least one of resources or data_sources is required"), nil
+	}
+
+	provider, err := registry.ParseProviderAddress(providerName, providerNamespace)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	client, err := provider.Client()
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	target, err := client.ResolveProviderVersion(ctx, provider.Namespace, provider.Name, providerVersion)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	checkInfo := map[string]interface{}{
+		"provider": provider.String(),
+		"version":  target.Version,
+	}
+
+	if fromVersion != "" {
+		from, err := client.ResolveProviderVersion(ctx, provider.Namespace, provider.Name, fromVersion)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		fromVersion = from.Version
+		checkInfo["from_version"] = fromVersion
+	}
+
+	// The upgrade guide is optional; providers without one are still checked against their docs
+	guide := ""
+	if _, guides, err := getCategoryDocs(ctx, client, provider, target.Version, "guides"); err == nil {
+		if doc, ok := upgradeGuide(guides, target.Version); ok {
+			if guide, err = client.GetProviderDocsContent(ctx, doc.ID); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			checkInfo["upgrade_guide"] = doc.Slug
+		}
+	}
+
+	type block struct{ category, name string }
+	blocks := []block{}
+	for _, name := range resources {
+		blocks = append(blocks, block{"resources", name})
+	}
+	for _, name := range dataSources {
+		blocks = append(blocks, block{"data-sources", name})
+	}
+
+	checks := make([]blockCheck, len(blocks))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(DEPRECATION_CHECK_CONCURRENCY)
+	for i, b := range blocks {
+		g.Go(func() error {
+			checks[i] = checkBlock(gctx, provider, target.Version, fromVersion, b.category, b.name, guide)
+			return gctx.Err()
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	summary := map[string]int{}
+	for _, check := range checks {
+		summary[check.Status]++
+	}
+	checkInfo["summary"] = summary
+	checkInfo["blocks"] = checks
+
+	checkInfoJSON, err := json.MarshalIndent(checkInfo, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Error marshaling deprecations: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(checkInfoJSON)), nil
 }
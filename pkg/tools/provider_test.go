@@ -2,9 +2,14 @@ package tools_test
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/Yunsang-Jeong/terraform-mcp-server/pkg/tools"
+	"github.com/Yunsang-Jeong/terraform-mcp-server/pkg/utils/registry"
 
 	"github.com/mark3labs/mcp-go/mcp"
 )
@@ -266,3 +271,751 @@ func TestGetDataBlockDocument_MissingBlockName(t *testing.T) {
 		t.Error("Expected error result for missing block_name")
 	}
 }
+
+// newTestRegistry points the default registry host at a local stand-in for the test
+func newTestRegistry(t *testing.T, handler http.Handler) {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	err := registry.Configure(registry.Config{
+		Hosts: map[string]string{registry.DEFAULT_REGISTRY_HOST: srv.URL},
+	})
+	if err != nil {
+		t.Fatalf("registry.Configure() unexpected error: %v", err)
+	}
+	t.Cleanup(func() { registry.Configure(registry.Config{}) })
+}
+
+func TestListProviderVersions_WithConstraint(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/providers/hashicorp/aws", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"included":[
+			{"type":"provider-versions","id":"10","attributes":{"version":"4.67.0","published-at":"2023-05-12T00:00:00Z"}},
+			{"type":"provider-versions","id":"11","attributes":{"version":"5.0.0","published-at":"2023-05-25T00:00:00Z"}},
+			{"type":"provider-versions","id":"12","attributes":{"version":"5.31.0","published-at":"2023-12-14T00:00:00Z"}}
+		]}`))
+	})
+	newTestRegistry(t, mux)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "list_provider_versions"
+	request.Params.Arguments = map[string]any{
+		"provider_name": "aws",
+		"constraint":    "~> 5.0",
+	}
+	result, err := tools.ListProviderVersions(context.Background(), request)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.IsError {
+		t.Fatalf("Expected success, got %+v", result.Content)
+	}
+
+	var versionsInfo struct {
+		Resolved string `json:"resolved"`
+		Versions []struct {
+			Version string `json:"version"`
+		} `json:"versions"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &versionsInfo); err != nil {
+		t.Fatalf("Expected JSON content, got %v", err)
+	}
+
+	if versionsInfo.Resolved != "5.31.0" {
+		t.Errorf("Expected resolved version 5.31.0, got %s", versionsInfo.Resolved)
+	}
+
+	if len(versionsInfo.Versions) != 2 {
+		t.Errorf("Expected 2 matching versions, got %d", len(versionsInfo.Versions))
+	}
+}
+
+func TestListProviderVersions_MissingProviderName(t *testing.T) {
+	ctx := context.Background()
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "list_provider_versions"
+	request.Params.Arguments = map[string]any{}
+	result, err := tools.ListProviderVersions(ctx, request)
+
+	if err != nil {
+		t.Fatalf("Expected no error from function, got %v", err)
+	}
+
+	if !result.IsError {
+		t.Error("Expected error result for missing provider_name")
+	}
+}
+
+func TestGetResourceBlockDocument_WithConstraint(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/providers/hashicorp/aws", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"included":[
+			{"type":"provider-versions","id":"11","attributes":{"version":"5.0.0"}},
+			{"type":"provider-versions","id":"12","attributes":{"version":"5.31.0"}}
+		]}`))
+	})
+	mux.HandleFunc("/v2/provider-docs", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("filter[provider-version]") != "12" {
+			w.Write([]byte(`{"data":[]}`))
+			return
+		}
+		w.Write([]byte(`{"data":[{"type":"provider-docs","id":"900"}]}`))
+	})
+	mux.HandleFunc("/v2/provider-docs/900", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{"id":"900","attributes":{"content":"# aws_s3_bucket 5.31.0"}}}`))
+	})
+	newTestRegistry(t, mux)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "search_resource_block_document"
+	request.Params.Arguments = map[string]any{
+		"provider_name":    "aws",
+		"provider_version": "~> 5.0",
+		"block_name":       "s3_bucket",
+	}
+	result, err := tools.GetResourceBlockDocument(context.Background(), request)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.IsError {
+		t.Fatalf("Expected success, got %+v", result.Content)
+	}
+
+	if text := result.Content[0].(mcp.TextContent).Text; text != "# aws_s3_bucket 5.31.0" {
+		t.Errorf("Unexpected content: %s", text)
+	}
+}
+
+func TestListProviderDocs_WithVersionAndSubcategory(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/providers/hashicorp/aws", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"included":[{"type":"provider-versions","id":"12","attributes":{"version":"5.31.0"}}]}`))
+	})
+	mux.HandleFunc("/v2/provider-docs", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":[
+			{"id":"1","attributes":{"category":"resources","slug":"s3_bucket","title":"aws_s3_bucket","subcategory":"S3 (Simple Storage)"}},
+			{"id":"2","attributes":{"category":"resources","slug":"instance","title":"aws_instance","subcategory":"EC2 (Elastic Compute Cloud)"}}
+		],"meta":{"pagination":{"current-page":1,"next-page":null}}}`))
+	})
+	newTestRegistry(t, mux)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "list_provider_docs"
+	request.Params.Arguments = map[string]any{
+		"provider_name":    "aws",
+		"provider_version": "5.31.0",
+		"category":         "resources",
+		"subcategory":      "s3 (simple storage)",
+	}
+	result, err := tools.ListProviderDocs(context.Background(), request)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.IsError {
+		t.Fatalf("Expected success, got %+v", result.Content)
+	}
+
+	var docsInfo struct {
+		Version string `json:"version"`
+		Docs    []struct {
+			Slug string `json:"slug"`
+		} `json:"docs"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &docsInfo); err != nil {
+		t.Fatalf("Expected JSON content, got %v", err)
+	}
+
+	if docsInfo.Version != "5.31.0" {
+		t.Errorf("Expected version 5.31.0, got %s", docsInfo.Version)
+	}
+
+	if len(docsInfo.Docs) != 1 || docsInfo.Docs[0].Slug != "s3_bucket" {
+		t.Errorf("Expected only s3_bucket, got %+v", docsInfo.Docs)
+	}
+}
+
+// newDocsTestRegistry serves a single provider version whose docs only match by exact slug lookup
+func newDocsTestRegistry(t *testing.T) {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/providers/hashicorp/aws", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"included":[{"type":"provider-versions","id":"12","attributes":{"version":"5.31.0"}}]}`))
+	})
+	mux.HandleFunc("/v2/provider-docs", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("filter[slug]") != "" {
+			w.Write([]byte(`{"data":[]}`))
+			return
+		}
+		w.Write([]byte(`{"data":[
+			{"id":"1","attributes":{"category":"resources","slug":"s3_bucket","title":"s3_bucket"}},
+			{"id":"2","attributes":{"category":"resources","slug":"s3_bucket_policy","title":"s3_bucket_policy"}},
+			{"id":"3","attributes":{"category":"resources","slug":"security_group","title":"security_group"}}
+		],"meta":{"pagination":{"current-page":1,"next-page":null}}}`))
+	})
+	mux.HandleFunc("/v2/provider-docs/1", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{"id":"1","attributes":{"content":"# aws_s3_bucket"}}}`))
+	})
+	newTestRegistry(t, mux)
+}
+
+func TestGetResourceBlockDocument_ProviderPrefixedName(t *testing.T) {
+	newDocsTestRegistry(t)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "search_resource_block_document"
+	request.Params.Arguments = map[string]any{
+		"provider_name":    "aws",
+		"provider_version": "5.31.0",
+		"block_name":       "aws_s3_bucket",
+	}
+	result, err := tools.GetResourceBlockDocument(context.Background(), request)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.IsError {
+		t.Fatalf("Expected success, got %+v", result.Content)
+	}
+
+	if text := result.Content[0].(mcp.TextContent).Text; text != "# aws_s3_bucket" {
+		t.Errorf("Unexpected content: %s", text)
+	}
+}
+
+func TestGetResourceBlockDocument_DidYouMean(t *testing.T) {
+	newDocsTestRegistry(t)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "search_resource_block_document"
+	request.Params.Arguments = map[string]any{
+		"provider_name":    "aws",
+		"provider_version": "5.31.0",
+		"block_name":       "s3_bukcet",
+	}
+	result, err := tools.GetResourceBlockDocument(context.Background(), request)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !result.IsError {
+		t.Fatalf("Expected error result for unknown block_name, got %+v", result.Content)
+	}
+
+	if text := result.Content[0].(mcp.TextContent).Text; !strings.Contains(text, "did you mean: s3_bucket") {
+		t.Errorf("Expected s3_bucket suggestion, got %s", text)
+	}
+}
+
+func TestSearchProviderDocs_Synonym(t *testing.T) {
+	newDocsTestRegistry(t)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "search_provider_docs"
+	request.Params.Arguments = map[string]any{
+		"provider_name":    "aws",
+		"provider_version": "5.31.0",
+		"query":            "aws_sg",
+		"limit":            1,
+	}
+	result, err := tools.SearchProviderDocs(context.Background(), request)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.IsError {
+		t.Fatalf("Expected success, got %+v", result.Content)
+	}
+
+	var searchInfo struct {
+		Matches []struct {
+			Slug string `json:"slug"`
+		} `json:"matches"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &searchInfo); err != nil {
+		t.Fatalf("Expected JSON content, got %v", err)
+	}
+
+	if len(searchInfo.Matches) != 1 || searchInfo.Matches[0].Slug != "security_group" {
+		t.Errorf("Expected only security_group, got %+v", searchInfo.Matches)
+	}
+}
+
+// newCategoryTestRegistry serves a provider version publishing resources, functions and guides
+func newCategoryTestRegistry(t *testing.T) {
+	t.Helper()
+
+	docs := map[string]string{
+		"resources": `{"id":"1","attributes":{"category":"resources","slug":"s3_bucket","title":"s3_bucket"}}`,
+		"functions": `{"id":"2","attributes":{"category":"functions","slug":"arn_parse","title":"arn_parse"}}`,
+		"guides": `{"id":"3","attributes":{"category":"guides","slug":"version-4-upgrade","title":"Version 4 Upgrade Guide"}},
+			{"id":"4","attributes":{"category":"guides","slug":"version-5-upgrade","title":"Version 5 Upgrade Guide"}}`,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/providers/hashicorp/aws", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"included":[{"type":"provider-versions","id":"12","attributes":{"version":"5.31.0"}}]}`))
+	})
+	mux.HandleFunc("/v2/provider-docs", func(w http.ResponseWriter, r *http.Request) {
+		data := []string{}
+		for category, doc := range docs {
+			if c := r.URL.Query().Get("filter[category]"); c == "" || c == category {
+				data = append(data, doc)
+			}
+		}
+		w.Write([]byte(`{"data":[` + strings.Join(data, ",") + `],"meta":{"pagination":{"current-page":1,"next-page":null}}}`))
+	})
+	mux.HandleFunc("/v2/provider-docs/", func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/v2/provider-docs/")
+		w.Write([]byte(`{"data":{"id":"` + id + `","attributes":{"content":"doc ` + id + `"}}}`))
+	})
+	newTestRegistry(t, mux)
+}
+
+func TestGetProviderFunction_CallSyntax(t *testing.T) {
+	newCategoryTestRegistry(t)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "get_provider_function"
+	request.Params.Arguments = map[string]any{
+		"provider_name":    "aws",
+		"provider_version": "5.31.0",
+		"function_name":    "provider::aws::arn_parse",
+	}
+	result, err := tools.GetProviderFunction(context.Background(), request)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.IsError {
+		t.Fatalf("Expected success, got %+v", result.Content)
+	}
+
+	if text := result.Content[0].(mcp.TextContent).Text; text != "doc 2" {
+		t.Errorf("Unexpected content: %s", text)
+	}
+}
+
+func TestGetProviderGuide_UpgradeGuide(t *testing.T) {
+	newCategoryTestRegistry(t)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "get_provider_guide"
+	request.Params.Arguments = map[string]any{
+		"provider_name":    "aws",
+		"provider_version": "~> 5.0",
+	}
+	result, err := tools.GetProviderGuide(context.Background(), request)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.IsError {
+		t.Fatalf("Expected success, got %+v", result.Content)
+	}
+
+	if text := result.Content[0].(mcp.TextContent).Text; text != "doc 4" {
+		t.Errorf("Expected version 5 upgrade guide, got %s", text)
+	}
+}
+
+func TestGetProviderDocument_UnpublishedCategory(t *testing.T) {
+	newCategoryTestRegistry(t)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "get_provider_document"
+	request.Params.Arguments = map[string]any{
+		"provider_name":    "aws",
+		"provider_version": "5.31.0",
+		"category":         "ephemeral-resources",
+		"slug":             "secretsmanager_secret_version",
+	}
+	result, err := tools.GetProviderDocument(context.Background(), request)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !result.IsError {
+		t.Fatalf("Expected error result for unpublished category, got %+v", result.Content)
+	}
+
+	if text := result.Content[0].(mcp.TextContent).Text; !strings.Contains(text, "available categories: functions, guides, resources") {
+		t.Errorf("Expected available categories, got %s", text)
+	}
+}
+
+func TestGetResourceBlockDocument_SchemaOutput(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/providers/hashicorp/aws", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"included":[{"type":"provider-versions","id":"12","attributes":{"version":"5.31.0"}}]}`))
+	})
+	mux.HandleFunc("/v2/provider-docs", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":[{"type":"provider-docs","id":"900"}]}`))
+	})
+	mux.HandleFunc("/v2/provider-docs/900", func(w http.ResponseWriter, r *http.Request) {
+		content := "# Resource: aws_s3_bucket\n\n## Argument Reference\n\n* `bucket` - (Required, Forces new resource) Name of the bucket.\n"
+		json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"id": "900", "attributes": map[string]any{"content": content}}})
+	})
+	newTestRegistry(t, mux)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "search_resource_block_document"
+	request.Params.Arguments = map[string]any{
+		"provider_name":    "aws",
+		"provider_version": "5.31.0",
+		"block_name":       "s3_bucket",
+		"output":           "schema",
+	}
+	result, err := tools.GetResourceBlockDocument(context.Background(), request)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.IsError {
+		t.Fatalf("Expected success, got %+v", result.Content)
+	}
+
+	var schema struct {
+		Name      string `json:"name"`
+		Arguments []struct {
+			Name     string `json:"name"`
+			Required bool   `json:"required"`
+			ForceNew bool   `json:"force_new"`
+		} `json:"arguments"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &schema); err != nil {
+		t.Fatalf("Expected JSON content, got %v", err)
+	}
+
+	if schema.Name != "aws_s3_bucket" || len(schema.Arguments) != 1 || !schema.Arguments[0].Required || !schema.Arguments[0].ForceNew {
+		t.Errorf("Unexpected schema: %+v", schema)
+	}
+}
+
+func TestGetResourceBlockDocument_InvalidOutput(t *testing.T) {
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "search_resource_block_document"
+	request.Params.Arguments = map[string]any{
+		"provider_name": "aws",
+		"block_name":    "s3_bucket",
+		"output":        "yaml",
+	}
+	result, err := tools.GetResourceBlockDocument(context.Background(), request)
+
+	if err != nil {
+		t.Fatalf("Expected no error from function, got %v", err)
+	}
+
+	if !result.IsError {
+		t.Error("Expected error result for invalid output")
+	}
+}
+
+func TestGetProviderDocument_SectionsAndCursor(t *testing.T) {
+	content := "# Resource: aws_instance\n\n## Example Usage\n\nexample\n\n## Argument Reference\n\n" +
+		strings.Repeat("* `arg` - (Optional) Argument.\n", 20) +
+		"\n## Import\n\nimport\n"
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/providers/hashicorp/aws", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"included":[{"type":"provider-versions","id":"12","attributes":{"version":"5.31.0"}}]}`))
+	})
+	mux.HandleFunc("/v2/provider-docs", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":[{"id":"1","attributes":{"category":"resources","slug":"instance","title":"instance"}}],"meta":{"pagination":{"current-page":1,"next-page":null}}}`))
+	})
+	mux.HandleFunc("/v2/provider-docs/1", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"id": "1", "attributes": map[string]any{"content": content}}})
+	})
+	newTestRegistry(t, mux)
+
+	var pages []string
+	cursor := ""
+	for range 10 {
+		request := mcp.CallToolRequest{}
+		request.Params.Name = "get_provider_document"
+		request.Params.Arguments = map[string]any{
+			"provider_name":    "aws",
+			"provider_version": "5.31.0",
+			"category":         "resources",
+			"slug":             "instance",
+			"sections":         []any{"Arguments Reference"},
+			"max_bytes":        200,
+			"cursor":           cursor,
+		}
+		result, err := tools.GetProviderDocument(context.Background(), request)
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if result.IsError {
+			t.Fatalf("Expected success, got %+v", result.Content)
+		}
+
+		pages = append(pages, result.Content[0].(mcp.TextContent).Text)
+		if len(result.Content) == 1 {
+			break
+		}
+
+		note := result.Content[1].(mcp.TextContent).Text
+		cursor = note[strings.Index(note, `cursor "`)+len(`cursor "`):]
+		cursor = cursor[:strings.Index(cursor, `"`)]
+	}
+
+	got := strings.Join(pages, "")
+	if len(pages) < 2 {
+		t.Errorf("Expected several pages, got %d", len(pages))
+	}
+	if !strings.HasPrefix(got, "## Argument Reference") || strings.Contains(got, "Example Usage") || strings.Contains(got, "## Import") {
+		t.Errorf("Unexpected sections: %s", got)
+	}
+	if strings.Count(got, "* `arg`") != 20 {
+		t.Errorf("Expected every argument across pages, got %d", strings.Count(got, "* `arg`"))
+	}
+}
+
+func TestGetProviderExamples(t *testing.T) {
+	content := "# Resource: aws_s3_bucket\n\n## Example Usage\n\n### Private Bucket\n\n" +
+		"```terraform\nresource \"aws_s3_bucket\" \"b\" {\n  bucket = \"my-tf-test-bucket\"\n}\n```\n\n" +
+		"## Argument Reference\n\n* `bucket` - (Optional) Name of the bucket.\n"
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/providers/hashicorp/aws", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"included":[{"type":"provider-versions","id":"12","attributes":{"version":"5.31.0"}}]}`))
+	})
+	mux.HandleFunc("/v2/provider-docs", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":[{"id":"1","attributes":{"category":"resources","slug":"s3_bucket","title":"s3_bucket"}}],"meta":{"pagination":{"current-page":1,"next-page":null}}}`))
+	})
+	mux.HandleFunc("/v2/provider-docs/1", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"id": "1", "attributes": map[string]any{"content": content}}})
+	})
+	newTestRegistry(t, mux)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "get_provider_examples"
+	request.Params.Arguments = map[string]any{
+		"provider_name":    "aws",
+		"provider_version": "5.31.0",
+		"block_name":       "aws_s3_bucket",
+	}
+	result, err := tools.GetProviderExamples(context.Background(), request)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.IsError {
+		t.Fatalf("Expected success, got %+v", result.Content)
+	}
+
+	var examplesInfo struct {
+		Examples []struct {
+			Section string `json:"section"`
+			Heading string `json:"heading"`
+			Valid   bool   `json:"valid"`
+		} `json:"examples"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &examplesInfo); err != nil {
+		t.Fatalf("Expected JSON content, got %v", err)
+	}
+
+	if len(examplesInfo.Examples) != 1 {
+		t.Fatalf("Expected 1 example, got %+v", examplesInfo.Examples)
+	}
+	if e := examplesInfo.Examples[0]; e.Section != "Example Usage" || e.Heading != "Private Bucket" || !e.Valid {
+		t.Errorf("Unexpected example: %+v", e)
+	}
+}
+
+func TestDiffProviderDocument(t *testing.T) {
+	contents := map[string]string{
+		"3": "# Resource: aws_s3_bucket\n\n## Argument Reference\n\n* `bucket` - (Optional) Name of the bucket.\n* `acl` - (Optional) Canned ACL.\n",
+		"5": "# Resource: aws_s3_bucket\n\n## Argument Reference\n\n* `bucket` - (Required) Name of the bucket.\n* `acl` - (Optional, **Deprecated**) Canned ACL.\n",
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/providers/hashicorp/aws", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"included":[
+			{"type":"provider-versions","id":"3","attributes":{"version":"3.76.1"}},
+			{"type":"provider-versions","id":"5","attributes":{"version":"5.31.0"}}
+		]}`))
+	})
+	mux.HandleFunc("/v2/provider-docs", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":[{"type":"provider-docs","id":"` + r.URL.Query().Get("filter[provider-version]") + `"}]}`))
+	})
+	mux.HandleFunc("/v2/provider-docs/", func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/v2/provider-docs/")
+		json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"id": id, "attributes": map[string]any{"content": contents[id]}}})
+	})
+	newTestRegistry(t, mux)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "diff_provider_document"
+	request.Params.Arguments = map[string]any{
+		"provider_name": "aws",
+		"block_name":    "s3_bucket",
+		"from_version":  "~> 3.0",
+		"to_version":    "~> 5.0",
+	}
+	result, err := tools.DiffProviderDocument(context.Background(), request)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.IsError {
+		t.Fatalf("Expected success, got %+v", result.Content)
+	}
+
+	var diffInfo struct {
+		FromVersion string `json:"from_version"`
+		ToVersion   string `json:"to_version"`
+		Changes     []struct {
+			Kind string `json:"kind"`
+			Path string `json:"path"`
+		} `json:"changes"`
+		Diff string `json:"diff"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &diffInfo); err != nil {
+		t.Fatalf("Expected JSON content, got %v", err)
+	}
+
+	if diffInfo.FromVersion != "3.76.1" || diffInfo.ToVersion != "5.31.0" {
+		t.Errorf("Unexpected versions: %s -> %s", diffInfo.FromVersion, diffInfo.ToVersion)
+	}
+
+	if len(diffInfo.Changes) != 2 || diffInfo.Changes[0].Kind != "deprecated" || diffInfo.Changes[1].Kind != "now_required" {
+		t.Errorf("Unexpected changes: %+v", diffInfo.Changes)
+	}
+
+	if !strings.Contains(diffInfo.Diff, "-* `bucket` - (Optional) Name of the bucket.\n") || !strings.Contains(diffInfo.Diff, "+* `bucket` - (Required) Name of the bucket.\n") {
+		t.Errorf("Unexpected diff: %s", diffInfo.Diff)
+	}
+}
+
+func TestCheckDeprecations(t *testing.T) {
+	type doc struct{ id, version, category, slug, content string }
+	docs := []doc{
+		{"41", "4", "resources", "s3_bucket", "# Resource: aws_s3_bucket\n\n## Argument Reference\n\n* `bucket` - (Optional) Name.\n* `acceleration_status` - (Optional) Status.\n"},
+		{"42", "4", "resources", "s3_bucket_object", "# Resource: aws_s3_bucket_object\n\n## Argument Reference\n\n* `key` - (Required) Key.\n"},
+		{"43", "4", "resources", "instance", "# Resource: aws_instance\n\n## Argument Reference\n\n* `ami` - (Optional) AMI.\n"},
+		{"44", "4", "resources", "vpc", "# Resource: aws_vpc\n\n## Argument Reference\n\n* `cidr_block` - (Optional) CIDR.\n"},
+		{"54", "5", "resources", "vpc", ""},
+		{"51", "5", "resources", "s3_bucket", "# Resource: aws_s3_bucket\n\n## Argument Reference\n\n* `bucket` - (Optional) Name.\n* `acl` - (Optional, **Deprecated**) Canned ACL.\n"},
+		{"52", "5", "resources", "s3_bucket_object", "# Resource: aws_s3_bucket_object\n\n!> **WARNING:** This resource is deprecated. Use `aws_s3_object` instead.\n\n## Argument Reference\n\n* `key` - (Required) Key.\n"},
+		{"53", "5", "guides", "version-5-upgrade", "# Version 5 Upgrade Guide\n\n## resource/aws_s3_bucket\n\nThe `acceleration_status` argument of `aws_s3_bucket` was removed.\n"},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/providers/hashicorp/aws", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"included":[
+			{"type":"provider-versions","id":"4","attributes":{"version":"4.67.0"}},
+			{"type":"provider-versions","id":"5","attributes":{"version":"5.31.0"}}
+		]}`))
+	})
+	mux.HandleFunc("/v2/provider-docs", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		data := []map[string]any{}
+		for _, d := range docs {
+			if d.version != query.Get("filter[provider-version]") || (query.Get("filter[category]") != "" && d.category != query.Get("filter[category]")) || (query.Get("filter[slug]") != "" && d.slug != query.Get("filter[slug]")) {
+				continue
+			}
+			data = append(data, map[string]any{"id": d.id, "attributes": map[string]any{"category": d.category, "slug": d.slug, "title": d.slug}})
+		}
+		json.NewEncoder(w).Encode(map[string]any{"data": data})
+	})
+	mux.HandleFunc("/v2/provider-docs/", func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/v2/provider-docs/")
+		// The registry fails to serve the target doc of aws_vpc
+		if id == "54" {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		for _, d := range docs {
+			if d.id == id {
+				json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"id": id, "attributes": map[string]any{"content": d.content}}})
+			}
+		}
+	})
+	newTestRegistry(t, mux)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "check_deprecations"
+	request.Params.Arguments = map[string]any{
+		"provider_name":    "aws",
+		"provider_version": "~> 5.0",
+		"from_version":     "4.67.0",
+		"resources":        []any{"aws_s3_bucket", "aws_s3_bucket_object", "aws_instance", "aws_vpc"},
+	}
+	result, err := tools.CheckDeprecations(context.Background(), request)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.IsError {
+		t.Fatalf("Expected success, got %+v", result.Content)
+	}
+
+	var checkInfo struct {
+		Version      string `json:"version"`
+		UpgradeGuide string `json:"upgrade_guide"`
+		Blocks       []struct {
+			Name         string   `json:"name"`
+			Status       string   `json:"status"`
+			Error        string   `json:"error"`
+			UpgradeGuide []string `json:"upgrade_guide_sections"`
+			Deprecations []struct {
+				Path string `json:"path"`
+			} `json:"deprecations"`
+			Removed []struct {
+				Path string `json:"path"`
+			} `json:"removed"`
+		} `json:"blocks"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &checkInfo); err != nil {
+		t.Fatalf("Expected JSON content, got %v", err)
+	}
+
+	if checkInfo.Version != "5.31.0" || checkInfo.UpgradeGuide != "version-5-upgrade" {
+		t.Errorf("Unexpected version or guide: %s, %s", checkInfo.Version, checkInfo.UpgradeGuide)
+	}
+
+	if len(checkInfo.Blocks) != 4 {
+		t.Fatalf("Expected 4 blocks, got %+v", checkInfo.Blocks)
+	}
+
+	bucket := checkInfo.Blocks[0]
+	if bucket.Status != "has_deprecations" || len(bucket.Deprecations) != 1 || bucket.Deprecations[0].Path != "acl" ||
+		len(bucket.Removed) != 1 || bucket.Removed[0].Path != "acceleration_status" || len(bucket.UpgradeGuide) != 1 {
+		t.Errorf("Unexpected aws_s3_bucket report: %+v", bucket)
+	}
+
+	if status := checkInfo.Blocks[1].Status; status != "deprecated" {
+		t.Errorf("Expected aws_s3_bucket_object deprecated, got %s", status)
+	}
+
+	if status := checkInfo.Blocks[2].Status; status != "removed" {
+		t.Errorf("Expected aws_instance removed, got %s", status)
+	}
+
+	if vpc := checkInfo.Blocks[3]; vpc.Status != "error" || vpc.Error == "" {
+		t.Errorf("Expected aws_vpc error when its doc cannot be fetched, got %+v", vpc)
+	}
+}
@@ -0,0 +1,258 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Yunsang-Jeong/terraform-mcp-server/pkg/utils/registry"
+
+	"github.com/Yunsang-Jeong/terraform-config-parser/pkg/parser"
+	"github.com/Yunsang-Jeong/terraform-config-parser/pkg/parser/schema"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// registryModuleConfig is a module described by the registry, in the shape
+// get_module reports a parsed configuration
+type registryModuleConfig struct {
+	*parser.TerraformConfig
+	Resources   []registry.RegistryV1ModuleResource   `json:"resources,omitempty"`
+	ModuleCalls []registry.RegistryV1ModuleDependency `json:"module_calls,omitempty"`
+}
+
+// registryModulePart is a submodule or an example of a registry module
+type registryModulePart struct {
+	Path   string               `json:"path"`
+	Config registryModuleConfig `json:"config"`
+}
+
+func SearchModules(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	client, err := registry.ClientFor(request.GetString("host", ""))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	search := registry.ModuleSearch{
+		Query:     request.GetString("query", ""),
+		Namespace: request.GetString("namespace", ""),
+		Provider:  request.GetString("provider", ""),
+		Verified:  request.GetBool("verified", false),
+		Offset:    request.GetInt("offset", 0),
+		Limit:     request.GetInt("limit", registry.DEFAULT_MODULE_SEARCH_LIMIT),
+	}
+	if search.Offset < 0 || search.Limit < 1 {
+		return mcp.NewToolResultError("offset must not be negative and limit must be positive"), nil
+	}
+
+	list, err := client.SearchModules(ctx, search)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	modules := []map[string]interface{}{}
+	for _, module := range list.Modules {
+		modules = append(modules, map[string]interface{}{
+			"module":       fmt.Sprintf("%s/%s/%s", module.Namespace, module.Name, module.Provider),
+			"version":      module.Version,
+			"description":  module.Description,
+			"source":       module.Source,
+			"downloads":    module.Downloads,
+			"verified":     module.Verified,
+			"published_at": module.PublishedAt,
+		})
+	}
+
+	searchInfo := map[string]interface{}{
+		"host":    client.Host(),
+		"modules": modules,
+	}
+	if list.Meta.NextOffset != nil {
+		searchInfo["next_offset"] = *list.Meta.NextOffset
+	}
+
+	searchInfoJSON, err := json.MarshalIndent(searchInfo, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Error marshaling modules: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(searchInfoJSON)), nil
+}
+
+func ListModuleVersions(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	source, err := request.RequireString("module")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	constraint := request.GetString("constraint", "")
+
+	module, err := registry.ParseModuleAddress(source)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	client, err := module.Client()
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	versions, err := client.ListModuleVersions(ctx, module.Namespace, module.Name, module.System)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	versionsInfo := map[string]interface{}{
+		"module": module.String(),
+	}
+
+	if constraint != "" {
+		matching, err := registry.MatchingVersions(versions, constraint)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if len(matching) == 0 {
+			return mcp.NewToolResultError(fmt.Sprintf("no version of %s matches %q", module, constraint)), nil
+		}
+
+		versionsInfo["constraint"] = constraint
+		versionsInfo["resolved"] = matching[0].Version
+		versions = matching
+	} else if latest, err := registry.ResolveVersion(versions, ""); err == nil {
+		versionsInfo["latest"] = latest.Version
+	}
+
+	versionList := []string{}
+	for _, v := range versions {
+		versionList = append(versionList, v.Version)
+	}
+	versionsInfo["versions"] = versionList
+
+	versionsInfoJSON, err := json.MarshalIndent(versionsInfo, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Error marshaling versions: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(versionsInfoJSON)), nil
+}
+
+// GetRegistryModule retrieves the inputs, outputs, dependencies, resources,
+// submodules and examples of a module published on a Terraform registry
+func GetRegistryModule(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	source, err := request.RequireString("module")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	version := request.GetString("version", "")
+
+	module, err := registry.ParseModuleAddress(source)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	client, err := module.Client()
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	// Without a version the registry answers with the latest one directly
+	if version != "" {
+		resolved, err := client.ResolveModuleVersion(ctx, module.Namespace, module.Name, module.System, version)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		version = resolved.Version
+	}
+
+	details, err := client.GetModule(ctx, module.Namespace, module.Name, module.System, version)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	submodules := []registryModulePart{}
+	for _, submodule := range details.Submodules {
+		submodules = append(submodules, registryModulePart{Path: submodule.Path, Config: newRegistryModuleConfig(submodule)})
+	}
+	examples := []registryModulePart{}
+	for _, example := range details.Examples {
+		examples = append(examples, registryModulePart{Path: example.Path, Config: newRegistryModuleConfig(example)})
+	}
+
+	moduleInfo := map[string]interface{}{
+		"module":      module.String(),
+		"version":     details.Version,
+		"description": details.Description,
+		"url":         details.Source,
+		"ref":         details.Tag,
+		"subdir":      details.Root.Path,
+		"config":      newRegistryModuleConfig(details.Root),
+		"submodules":  submodules,
+		"examples":    examples,
+	}
+
+	moduleInfoJSON, err := json.MarshalIndent(moduleInfo, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Error marshaling module info: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(moduleInfoJSON)), nil
+}
+
+func newRegistryModuleConfig(body registry.RegistryV1ModuleBody) registryModuleConfig {
+	config := registryModuleConfig{
+		TerraformConfig: &parser.TerraformConfig{
+			Variables: []*schema.Variable{},
+			Outputs:   []*schema.Output{},
+			Terraform: []*schema.Terraform{},
+		},
+		Resources:   body.Resources,
+		ModuleCalls: body.Dependencies,
+	}
+
+	for _, input := range body.Inputs {
+		config.Variables = append(config.Variables, &schema.Variable{
+			Name:        input.Name,
+			Description: input.Description,
+			Type:        input.Type,
+			Default:     registryDefault(input.Default),
+			Required:    input.Required,
+		})
+	}
+
+	for _, output := range body.Outputs {
+		config.Outputs = append(config.Outputs, &schema.Output{Name: output.Name, Description: output.Description})
+	}
+
+	if len(body.ProviderDependencies) > 0 {
+		providers := map[string]*schema.RequiredProvider{}
+		for _, dep := range body.ProviderDependencies {
+			source := dep.Source
+			if source == "" && dep.Namespace != "" {
+				source = dep.Namespace + "/" + dep.Name
+			}
+			providers[dep.Name] = &schema.RequiredProvider{Source: source, Version: dep.Version}
+		}
+		config.Terraform = append(config.Terraform, &schema.Terraform{RequiredProviders: providers})
+	}
+
+	return config
+}
+
+// registryDefault decodes the JSON encoded default of a registry input. As in
+// get_module, scalars are returned as values and collections as their source text.
+func registryDefault(raw string) interface{} {
+	if raw == "" {
+		return nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal([]byte(raw), &value); err != nil {
+		return raw
+	}
+
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		return raw
+	}
+	return value
+}
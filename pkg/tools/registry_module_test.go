@@ -0,0 +1,178 @@
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Yunsang-Jeong/terraform-mcp-server/pkg/tools"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func newModuleTestRegistry(t *testing.T) {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/modules/search", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"meta":{"limit":2,"current_offset":0,"next_offset":2},"modules":[
+			{"namespace":"terraform-aws-modules","name":"vpc","provider":"aws","version":"5.8.1","downloads":100},
+			{"namespace":"cloudposse","name":"vpc","provider":"aws","version":"2.1.0","verified":true}
+		]}`))
+	})
+	mux.HandleFunc("/v1/modules/terraform-aws-modules/vpc/aws/versions", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"modules":[{"versions":[{"version":"4.0.2"},{"version":"5.8.1"},{"version":"5.0.0"}]}]}`))
+	})
+	mux.HandleFunc("/v1/modules/terraform-aws-modules/vpc/aws/5.8.1", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"namespace":"terraform-aws-modules","name":"vpc","provider":"aws","version":"5.8.1",
+			"source":"https://github.com/terraform-aws-modules/terraform-aws-vpc","tag":"v5.8.1",
+			"root":{
+				"path":"",
+				"inputs":[
+					{"name":"cidr","type":"string","default":"\"10.0.0.0/16\"","required":false},
+					{"name":"azs","type":"list(string)","default":"[]","required":false},
+					{"name":"name","type":"string","default":"","required":true}
+				],
+				"outputs":[{"name":"vpc_id","description":"The ID of the VPC"}],
+				"provider_dependencies":[{"name":"aws","namespace":"hashicorp","source":"hashicorp/aws","version":">= 5.30"}],
+				"resources":[{"name":"this","type":"aws_vpc"}]
+			},
+			"submodules":[{"path":"modules/vpc-endpoints","inputs":[{"name":"vpc_id","type":"string","required":true}]}],
+			"examples":[{"path":"examples/simple","dependencies":[{"name":"vpc","source":"../../"}]}]
+		}`))
+	})
+	newTestRegistry(t, mux)
+}
+
+func TestSearchModules(t *testing.T) {
+	newModuleTestRegistry(t)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "search_modules"
+	request.Params.Arguments = map[string]any{"query": "vpc", "provider": "aws", "limit": float64(2)}
+	result, err := tools.SearchModules(context.Background(), request)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.IsError {
+		t.Fatalf("Expected success, got %+v", result.Content)
+	}
+
+	text := result.Content[0].(mcp.TextContent).Text
+	if !strings.Contains(text, `"module": "terraform-aws-modules/vpc/aws"`) || !strings.Contains(text, `"next_offset": 2`) {
+		t.Errorf("Unexpected search result: %s", text)
+	}
+}
+
+func TestListModuleVersions(t *testing.T) {
+	newModuleTestRegistry(t)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "list_module_versions"
+	request.Params.Arguments = map[string]any{"module": "terraform-aws-modules/vpc/aws", "constraint": "~> 5.0"}
+	result, err := tools.ListModuleVersions(context.Background(), request)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.IsError {
+		t.Fatalf("Expected success, got %+v", result.Content)
+	}
+
+	var versionsInfo struct {
+		Resolved string   `json:"resolved"`
+		Versions []string `json:"versions"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &versionsInfo); err != nil {
+		t.Fatalf("Expected JSON content, got %v", err)
+	}
+
+	if versionsInfo.Resolved != "5.8.1" || len(versionsInfo.Versions) != 2 {
+		t.Errorf("Unexpected versions: %+v", versionsInfo)
+	}
+}
+
+func TestGetRegistryModule(t *testing.T) {
+	newModuleTestRegistry(t)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "get_registry_module"
+	request.Params.Arguments = map[string]any{"module": "terraform-aws-modules/vpc/aws", "version": "~> 5.8"}
+	result, err := tools.GetRegistryModule(context.Background(), request)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.IsError {
+		t.Fatalf("Expected success, got %+v", result.Content)
+	}
+
+	var moduleInfo struct {
+		Version string `json:"version"`
+		Ref     string `json:"ref"`
+		Config  struct {
+			Variables []struct {
+				Name     string      `json:"name"`
+				Default  interface{} `json:"default"`
+				Required bool        `json:"required"`
+			} `json:"variables"`
+			Outputs   []struct{ Name string } `json:"outputs"`
+			Terraform []struct {
+				RequiredProviders map[string]struct{ Source, Version string } `json:"required_providers"`
+			} `json:"terraform"`
+			Resources []struct{ Type string } `json:"resources"`
+		} `json:"config"`
+		Submodules []struct{ Path string } `json:"submodules"`
+		Examples   []struct {
+			Config struct {
+				ModuleCalls []struct{ Source string } `json:"module_calls"`
+			} `json:"config"`
+		} `json:"examples"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &moduleInfo); err != nil {
+		t.Fatalf("Expected JSON content, got %v", err)
+	}
+
+	if moduleInfo.Version != "5.8.1" || moduleInfo.Ref != "v5.8.1" {
+		t.Errorf("Unexpected version: %+v", moduleInfo)
+	}
+
+	variables := moduleInfo.Config.Variables
+	if len(variables) != 3 || variables[0].Default != "10.0.0.0/16" || variables[1].Default != "[]" || variables[2].Default != nil || !variables[2].Required {
+		t.Errorf("Unexpected variables: %+v", variables)
+	}
+
+	if len(moduleInfo.Config.Terraform) != 1 || moduleInfo.Config.Terraform[0].RequiredProviders["aws"].Source != "hashicorp/aws" {
+		t.Errorf("Unexpected terraform block: %+v", moduleInfo.Config.Terraform)
+	}
+
+	if len(moduleInfo.Config.Outputs) != 1 || len(moduleInfo.Config.Resources) != 1 || len(moduleInfo.Submodules) != 1 {
+		t.Errorf("Unexpected module: %+v", moduleInfo)
+	}
+
+	if len(moduleInfo.Examples) != 1 || len(moduleInfo.Examples[0].Config.ModuleCalls) != 1 || moduleInfo.Examples[0].Config.ModuleCalls[0].Source != "../../" {
+		t.Errorf("Unexpected examples: %+v", moduleInfo.Examples)
+	}
+}
+
+func TestGetRegistryModule_InvalidAddress(t *testing.T) {
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "get_registry_module"
+	request.Params.Arguments = map[string]any{"module": "terraform-aws-modules/vpc"}
+	result, err := tools.GetRegistryModule(context.Background(), request)
+
+	if err != nil {
+		t.Fatalf("Expected no error from function, got %v", err)
+	}
+
+	if !result.IsError {
+		t.Error("Expected error result for an address without a system")
+	}
+}
@@ -0,0 +1,223 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Yunsang-Jeong/terraform-mcp-server/pkg/utils"
+	"github.com/Yunsang-Jeong/terraform-mcp-server/pkg/utils/docschema"
+	"github.com/Yunsang-Jeong/terraform-mcp-server/pkg/utils/localdir"
+	"github.com/Yunsang-Jeong/terraform-mcp-server/pkg/utils/providerschema"
+	"github.com/Yunsang-Jeong/terraform-mcp-server/pkg/utils/registry"
+
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// schemaKindCategories maps schema block kinds to the registry doc category used as fallback
+var schemaKindCategories = map[string]string{
+	providerschema.KIND_PROVIDER:  "overview",
+	providerschema.KIND_RESOURCE:  "resources",
+	providerschema.KIND_DATA:      "data-sources",
+	providerschema.KIND_EPHEMERAL: "ephemeral-resources",
+	providerschema.KIND_LIST:      "list-resources",
+}
+
+func LoadProviderSchema(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	path := request.GetString("path", "")
+	inline := request.GetString("json", "")
+
+	if (path == "") == (inline == "") {
+		return mcp.NewToolResultError("exactly one of path or json is required"), nil
+	}
+
+	store := providerschema.Default()
+
+	var loaded []string
+	var err error
+	if path != "" {
+		// Files are only read inside the local roots; others are loaded with --provider-schema-file
+		file, resolveErr := localdir.ResolveFile(path)
+		if resolveErr != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid schema path: %v", resolveErr)), nil
+		}
+		loaded, err = store.LoadFile(file)
+	} else {
+		loaded, err = store.Load([]byte(inline))
+	}
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	loadInfo := map[string]interface{}{
+		"loaded":    loaded,
+		"providers": store.Providers(),
+	}
+
+	loadInfoJSON, err := json.MarshalIndent(loadInfo, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Error marshaling providers: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(loadInfoJSON)), nil
+}
+
+func GetProviderSchema(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	providerNamespace := request.GetString("provider_namespace", "hashicorp")
+
+	providerName, err := request.RequireString("provider_name")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	providerVersion := request.GetString("provider_version", "")
+	kind := request.GetString("block_type", providerschema.KIND_RESOURCE)
+	blockName := request.GetString("block_name", "")
+	path := request.GetString("path", "")
+
+	if !utils.IsInList(kind, providerschema.KINDS) {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid block_type: %s", kind)), nil
+	}
+	if kind != providerschema.KIND_PROVIDER && blockName == "" {
+		return mcp.NewToolResultError("block_name is required"), nil
+	}
+
+	provider, err := registry.ParseProviderAddress(providerName, providerNamespace)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	schemaInfo := map[string]interface{}{
+		"provider":   provider.String(),
+		"block_type": kind,
+	}
+	if blockName != "" {
+		schemaInfo["block_name"] = blockName
+	}
+
+	if block, ok := providerschema.Default().Lookup(provider, kind, blockName); ok {
+		schemaInfo["source"] = "schema"
+
+		if path != "" {
+			element, err := providerschema.Find(block, path)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			schemaInfo["element"] = element
+		} else {
+			schemaInfo["schema"] = providerschema.Describe(block)
+		}
+	} else {
+		// Not in a loaded schema: fall back to what the registry docs say
+		name := blockName
+		if kind == providerschema.KIND_PROVIDER {
+			name = "index"
+		}
+
+		contents, err := getCategoryDocument(ctx, provider, providerVersion, schemaKindCategories[kind], name)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		schemaInfo["source"] = "registry"
+		schemaInfo["schema"] = docschema.Parse(contents)
+		if path != "" {
+			schemaInfo["note"] = "path is only resolved against a loaded provider schema; the whole document schema is returned"
+		}
+	}
+
+	schemaInfoJSON, err := json.MarshalIndent(schemaInfo, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Error marshaling schema: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(schemaInfoJSON)), nil
+}
+
+func ValidateHCL(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	src, err := request.RequireString("hcl")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	providerNamespace := request.GetString("provider_namespace", "hashicorp")
+	providerName := request.GetString("provider_name", "")
+	providerVersion := request.GetString("provider_version", "")
+
+	// Where the schema of each block type came from, "schema" or "registry"
+	sources := map[string]string{}
+
+	schemaOf := func(kind, typeName string) (*tfjson.SchemaBlock, error) {
+		name := providerName
+		if name == "" {
+			// The provider local name is the type name prefix ("aws" in "aws_s3_bucket")
+			name, _, _ = strings.Cut(typeName, "_")
+		}
+		provider, err := registry.ParseProviderAddress(name, providerNamespace)
+		if err != nil {
+			return nil, err
+		}
+
+		key := kind + "." + typeName
+		if block, ok := providerschema.Default().Lookup(provider, kind, typeName); ok {
+			sources[key] = "schema"
+			return block, nil
+		}
+
+		docName := typeName
+		if kind == providerschema.KIND_PROVIDER {
+			docName = "index"
+		}
+		contents, err := getCategoryDocument(ctx, provider, providerVersion, schemaKindCategories[kind], docName)
+		if err != nil {
+			return nil, err
+		}
+
+		sources[key] = "registry"
+		return providerschema.FromDoc(docschema.Parse(contents)), nil
+	}
+
+	diagnostics := providerschema.Validate([]byte(src), "main.tf", schemaOf)
+
+	// Registry docs often leave arguments out, so what they say about the
+	// arguments and nested blocks of a block is only a warning
+	for i, diag := range diagnostics {
+		kind, rest, _ := strings.Cut(diag.Address, ".")
+		typeName, _, _ := strings.Cut(rest, ".")
+		if diag.Path != "" && diag.Severity == providerschema.SEVERITY_ERROR && sources[kind+"."+typeName] == "registry" {
+			diagnostics[i].Severity = providerschema.SEVERITY_WARNING
+		}
+	}
+
+	errors, warnings := 0, 0
+	for _, diag := range diagnostics {
+		if diag.Severity == providerschema.SEVERITY_ERROR {
+			errors++
+		} else {
+			warnings++
+		}
+	}
+
+	validation := map[string]interface{}{
+		"valid":       errors == 0,
+		"errors":      errors,
+		"warnings":    warnings,
+		"diagnostics": diagnostics,
+		"sources":     sources,
+	}
+	for _, source := range sources {
+		if source == "registry" {
+			validation["note"] = "block types from the registry docs are checked against what the docs describe and their argument problems are reported as warnings; load a provider schema for exact checks"
+			break
+		}
+	}
+
+	validationJSON, err := json.MarshalIndent(validation, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Error marshaling diagnostics: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(validationJSON)), nil
+}
@@ -0,0 +1,280 @@
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Yunsang-Jeong/terraform-mcp-server/pkg/tools"
+	"github.com/Yunsang-Jeong/terraform-mcp-server/pkg/utils/localdir"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const testProviderSchema = `{
+  "format_version": "1.0",
+  "provider_schemas": {
+    "registry.terraform.io/hashicorp/random": {
+      "resource_schemas": {
+        "random_password": {
+          "block": {
+            "attributes": {
+              "length": {"type": "number", "required": true},
+              "result": {"type": "string", "computed": true, "sensitive": true},
+              "keepers": {"type": ["map", "string"], "optional": true}
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+func loadTestProviderSchema(t *testing.T) {
+	t.Helper()
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "load_provider_schema"
+	request.Params.Arguments = map[string]any{"json": testProviderSchema}
+	result, err := tools.LoadProviderSchema(context.Background(), request)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.IsError {
+		t.Fatalf("Expected success, got %+v", result.Content)
+	}
+}
+
+func TestLoadProviderSchema_RequiresOneSource(t *testing.T) {
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "load_provider_schema"
+	request.Params.Arguments = map[string]any{}
+	result, err := tools.LoadProviderSchema(context.Background(), request)
+
+	if err != nil {
+		t.Fatalf("Expected no error from function, got %v", err)
+	}
+
+	if !result.IsError {
+		t.Error("Expected error result without path or json")
+	}
+}
+
+func TestLoadProviderSchema_PathInsideLocalRoots(t *testing.T) {
+	root := t.TempDir()
+	os.WriteFile(filepath.Join(root, "schema.json"), []byte(testProviderSchema), 0o644)
+	outside := filepath.Join(t.TempDir(), "schema.json")
+	os.WriteFile(outside, []byte(testProviderSchema), 0o644)
+
+	if err := localdir.Configure([]string{root}); err != nil {
+		t.Fatalf("localdir.Configure() unexpected error: %v", err)
+	}
+	t.Cleanup(func() { localdir.Configure(nil) })
+
+	tests := []struct {
+		path    string
+		wantErr bool
+	}{
+		{path: "schema.json"},
+		{path: outside, wantErr: true},
+		{path: "/etc/passwd", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		request := mcp.CallToolRequest{}
+		request.Params.Name = "load_provider_schema"
+		request.Params.Arguments = map[string]any{"path": tt.path}
+		result, err := tools.LoadProviderSchema(context.Background(), request)
+
+		if err != nil {
+			t.Fatalf("Expected no error from function, got %v", err)
+		}
+		if result.IsError != tt.wantErr {
+			t.Errorf("LoadProviderSchema(%q) error = %v, want %v: %+v", tt.path, result.IsError, tt.wantErr, result.Content)
+		}
+	}
+}
+
+func TestGetProviderSchema_FromLoadedSchema(t *testing.T) {
+	loadTestProviderSchema(t)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "get_provider_schema"
+	request.Params.Arguments = map[string]any{
+		"provider_name": "random",
+		"block_name":    "password",
+		"path":          "result",
+	}
+	result, err := tools.GetProviderSchema(context.Background(), request)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.IsError {
+		t.Fatalf("Expected success, got %+v", result.Content)
+	}
+
+	var schemaInfo struct {
+		Source  string `json:"source"`
+		Element struct {
+			Attribute struct {
+				Type      string `json:"type"`
+				Sensitive bool   `json:"sensitive"`
+			} `json:"attribute"`
+		} `json:"element"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &schemaInfo); err != nil {
+		t.Fatalf("Expected JSON content, got %v", err)
+	}
+
+	if schemaInfo.Source != "schema" || schemaInfo.Element.Attribute.Type != "string" || !schemaInfo.Element.Attribute.Sensitive {
+		t.Errorf("Unexpected schema: %+v", schemaInfo)
+	}
+}
+
+func TestGetProviderSchema_RegistryFallback(t *testing.T) {
+	loadTestProviderSchema(t)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/providers/hashicorp/random", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"included":[{"type":"provider-versions","id":"7","attributes":{"version":"3.6.0"}}]}`))
+	})
+	mux.HandleFunc("/v2/provider-docs", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":[{"id":"70","attributes":{"category":"resources","slug":"string","title":"string"}}]}`))
+	})
+	mux.HandleFunc("/v2/provider-docs/70", func(w http.ResponseWriter, r *http.Request) {
+		content := "# random_string\n\n## Argument Reference\n\n* `length` - (Required) The length of the string.\n"
+		json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"id": "70", "attributes": map[string]any{"content": content}}})
+	})
+	newTestRegistry(t, mux)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "get_provider_schema"
+	request.Params.Arguments = map[string]any{
+		"provider_name":    "random",
+		"provider_version": "3.6.0",
+		"block_name":       "random_string",
+	}
+	result, err := tools.GetProviderSchema(context.Background(), request)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.IsError {
+		t.Fatalf("Expected success, got %+v", result.Content)
+	}
+
+	text := result.Content[0].(mcp.TextContent).Text
+	if !strings.Contains(text, `"source": "registry"`) || !strings.Contains(text, `"name": "length"`) {
+		t.Errorf("Unexpected fallback: %s", text)
+	}
+}
+
+func TestValidateHCL_FromLoadedSchema(t *testing.T) {
+	loadTestProviderSchema(t)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "validate_hcl"
+	request.Params.Arguments = map[string]any{
+		"hcl": "resource \"random_password\" \"db\" {\n  lenght = 16\n}\n",
+	}
+	result, err := tools.ValidateHCL(context.Background(), request)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.IsError {
+		t.Fatalf("Expected success, got %+v", result.Content)
+	}
+
+	var validation struct {
+		Valid       bool `json:"valid"`
+		Diagnostics []struct {
+			Summary string `json:"summary"`
+			Detail  string `json:"detail"`
+		} `json:"diagnostics"`
+		Sources map[string]string `json:"sources"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &validation); err != nil {
+		t.Fatalf("Expected JSON content, got %v", err)
+	}
+
+	if validation.Valid || len(validation.Diagnostics) != 2 {
+		t.Fatalf("Unexpected validation: %+v", validation)
+	}
+	if validation.Diagnostics[0].Summary != "Missing required argument" || !strings.Contains(validation.Diagnostics[1].Detail, `Did you mean "length"?`) {
+		t.Errorf("Unexpected diagnostics: %+v", validation.Diagnostics)
+	}
+	if validation.Sources["resource.random_password"] != "schema" {
+		t.Errorf("Unexpected sources: %+v", validation.Sources)
+	}
+}
+
+func TestValidateHCL_RegistryFallback(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/providers/hashicorp/random", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"included":[{"type":"provider-versions","id":"7","attributes":{"version":"3.6.0"}}]}`))
+	})
+	mux.HandleFunc("/v2/provider-docs", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":[{"id":"71","attributes":{"category":"resources","slug":"id","title":"id"}}]}`))
+	})
+	mux.HandleFunc("/v2/provider-docs/71", func(w http.ResponseWriter, r *http.Request) {
+		content := "# random_id\n\n## Argument Reference\n\n* `byte_length` - (Required, Number) The number of random bytes to produce.\n"
+		json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"id": "71", "attributes": map[string]any{"content": content}}})
+	})
+	newTestRegistry(t, mux)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "validate_hcl"
+	request.Params.Arguments = map[string]any{
+		"hcl":              "resource \"random_id\" \"server\" {\n  byte_length = \"eight\"\n  prefix      = \"srv-\"\n}\n",
+		"provider_version": "3.6.0",
+	}
+	result, err := tools.ValidateHCL(context.Background(), request)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.IsError {
+		t.Fatalf("Expected success, got %+v", result.Content)
+	}
+
+	text := result.Content[0].(mcp.TextContent).Text
+	if !strings.Contains(text, `"resource.random_id": "registry"`) || !strings.Contains(text, "Incorrect attribute value type") {
+		t.Errorf("Unexpected validation: %s", text)
+	}
+
+	// The docs do not mention "prefix": a guess from the docs does not fail the configuration
+	var validation struct {
+		Valid       bool `json:"valid"`
+		Diagnostics []struct {
+			Severity string `json:"severity"`
+			Summary  string `json:"summary"`
+			Path     string `json:"path"`
+		} `json:"diagnostics"`
+	}
+	if err := json.Unmarshal([]byte(text), &validation); err != nil {
+		t.Fatalf("Expected JSON content, got %v", err)
+	}
+	if !validation.Valid || len(validation.Diagnostics) != 2 {
+		t.Fatalf("Unexpected validation: %+v", validation)
+	}
+	for _, diag := range validation.Diagnostics {
+		if diag.Severity != "warning" {
+			t.Errorf("Expected a warning for %s, got %+v", diag.Path, diag)
+		}
+	}
+	if validation.Diagnostics[1].Summary != "Unsupported argument" || validation.Diagnostics[1].Path != "prefix" {
+		t.Errorf("Unexpected diagnostics: %+v", validation.Diagnostics)
+	}
+}
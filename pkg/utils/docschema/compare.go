@@ -0,0 +1,154 @@
+package docschema
+
+import (
+	"fmt"
+	"sort"
+)
+
+const (
+	CHANGE_ADDED           = "added"
+	CHANGE_REMOVED         = "removed"
+	CHANGE_DEPRECATED      = "deprecated"
+	CHANGE_UNDEPRECATED    = "undeprecated"
+	CHANGE_NOW_REQUIRED    = "now_required"
+	CHANGE_NOW_OPTIONAL    = "now_optional"
+	CHANGE_NOW_FORCE_NEW   = "now_force_new"
+	CHANGE_DEFAULT_CHANGED = "default_changed"
+)
+
+// Change is a difference between two versions of a document. Path is the
+// argument or attribute name, prefixed with its block ("rule.expiration").
+type Change struct {
+	Kind    string `json:"kind"`
+	Element string `json:"element"`
+	Path    string `json:"path"`
+	Detail  string `json:"detail,omitempty"`
+}
+
+// Compare lists the arguments, blocks and attributes added, removed or
+// changed in meaning between two versions of a document
+func Compare(from, to Schema) []Change {
+	changes := []Change{}
+
+	fromArgs, toArgs := argumentsByPath(from), argumentsByPath(to)
+	for path, arg := range toArgs {
+		old, ok := fromArgs[path]
+		if !ok {
+			changes = append(changes, Change{Kind: CHANGE_ADDED, Element: "argument", Path: path, Detail: requiredness(arg)})
+			continue
+		}
+		changes = append(changes, compareArgument(path, old, arg)...)
+	}
+	for path, arg := range fromArgs {
+		if _, ok := toArgs[path]; !ok {
+			changes = append(changes, Change{Kind: CHANGE_REMOVED, Element: "argument", Path: path, Detail: requiredness(arg)})
+		}
+	}
+
+	fromBlocks, toBlocks := blockNames(from), blockNames(to)
+	for name := range toBlocks {
+		if !fromBlocks[name] {
+			changes = append(changes, Change{Kind: CHANGE_ADDED, Element: "block", Path: name})
+		}
+	}
+	for name := range fromBlocks {
+		if !toBlocks[name] {
+			changes = append(changes, Change{Kind: CHANGE_REMOVED, Element: "block", Path: name})
+		}
+	}
+
+	fromAttrs, toAttrs := attributePaths(from), attributePaths(to)
+	for path := range toAttrs {
+		if !fromAttrs[path] {
+			changes = append(changes, Change{Kind: CHANGE_ADDED, Element: "attribute", Path: path})
+		}
+	}
+	for path := range fromAttrs {
+		if !toAttrs[path] {
+			changes = append(changes, Change{Kind: CHANGE_REMOVED, Element: "attribute", Path: path})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Element != changes[j].Element {
+			return changes[i].Element < changes[j].Element
+		}
+		if changes[i].Path != changes[j].Path {
+			return changes[i].Path < changes[j].Path
+		}
+		return changes[i].Kind < changes[j].Kind
+	})
+
+	return changes
+}
+
+func compareArgument(path string, from, to Argument) []Change {
+	changes := []Change{}
+	change := func(kind, detail string) {
+		changes = append(changes, Change{Kind: kind, Element: "argument", Path: path, Detail: detail})
+	}
+
+	switch {
+	case !from.Deprecated && to.Deprecated:
+		change(CHANGE_DEPRECATED, to.Description)
+	case from.Deprecated && !to.Deprecated:
+		change(CHANGE_UNDEPRECATED, "")
+	}
+
+	switch {
+	case !from.Required && to.Required:
+		change(CHANGE_NOW_REQUIRED, "")
+	case from.Required && !to.Required:
+		change(CHANGE_NOW_OPTIONAL, "")
+	}
+
+	if !from.ForceNew && to.ForceNew {
+		change(CHANGE_NOW_FORCE_NEW, "")
+	}
+
+	if from.Default != to.Default && from.Default != "" && to.Default != "" {
+		change(CHANGE_DEFAULT_CHANGED, fmt.Sprintf("%s -> %s", from.Default, to.Default))
+	}
+
+	return changes
+}
+
+func requiredness(arg Argument) string {
+	if arg.Required {
+		return "required"
+	}
+	return "optional"
+}
+
+func argumentsByPath(schema Schema) map[string]Argument {
+	args := map[string]Argument{}
+	for _, arg := range schema.Arguments {
+		args[arg.Name] = arg
+	}
+	for _, block := range schema.Blocks {
+		for _, arg := range block.Arguments {
+			args[block.Name+"."+arg.Name] = arg
+		}
+	}
+	return args
+}
+
+func blockNames(schema Schema) map[string]bool {
+	names := map[string]bool{}
+	for _, block := range schema.Blocks {
+		names[block.Name] = true
+	}
+	return names
+}
+
+func attributePaths(schema Schema) map[string]bool {
+	paths := map[string]bool{}
+	for _, attr := range schema.Attributes {
+		if attr.Block != "" {
+			paths[attr.Block+"."+attr.Name] = true
+		} else {
+			paths[attr.Name] = true
+		}
+	}
+	return paths
+}
@@ -0,0 +1,48 @@
+package docschema
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	from := Schema{
+		Arguments: []Argument{
+			{Name: "bucket"},
+			{Name: "acl", Default: "private"},
+			{Name: "versioning", Type: "block"},
+			{Name: "region"},
+		},
+		Blocks: []Block{
+			{Name: "versioning", Arguments: []Argument{{Name: "enabled", Default: "false"}}},
+		},
+		Attributes: []Attribute{{Name: "id"}, {Name: "region"}},
+	}
+	to := Schema{
+		Arguments: []Argument{
+			{Name: "bucket", Required: true, ForceNew: true},
+			{Name: "acl", Deprecated: true, Default: "private", Description: "Use aws_s3_bucket_acl instead."},
+			{Name: "versioning", Type: "block"},
+			{Name: "force_destroy"},
+		},
+		Blocks: []Block{
+			{Name: "versioning", Arguments: []Argument{{Name: "enabled", Default: "true"}}},
+		},
+		Attributes: []Attribute{{Name: "id"}, {Name: "bucket_domain_name"}},
+	}
+
+	want := []Change{
+		{Kind: CHANGE_DEPRECATED, Element: "argument", Path: "acl", Detail: "Use aws_s3_bucket_acl instead."},
+		{Kind: CHANGE_NOW_FORCE_NEW, Element: "argument", Path: "bucket"},
+		{Kind: CHANGE_NOW_REQUIRED, Element: "argument", Path: "bucket"},
+		{Kind: CHANGE_ADDED, Element: "argument", Path: "force_destroy", Detail: "optional"},
+		{Kind: CHANGE_REMOVED, Element: "argument", Path: "region", Detail: "optional"},
+		{Kind: CHANGE_DEFAULT_CHANGED, Element: "argument", Path: "versioning.enabled", Detail: "false -> true"},
+		{Kind: CHANGE_ADDED, Element: "attribute", Path: "bucket_domain_name"},
+		{Kind: CHANGE_REMOVED, Element: "attribute", Path: "region"},
+	}
+
+	if got := Compare(from, to); !reflect.DeepEqual(got, want) {
+		t.Errorf("Compare() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,91 @@
+package docschema
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Deprecation is a deprecated argument or attribute of a document
+type Deprecation struct {
+	Element string `json:"element"`
+	Path    string `json:"path"`
+	Note    string `json:"note,omitempty"`
+}
+
+// Deprecations returns the notes deprecating the document as a whole, found
+// in its front matter or the introduction before the first section, and the
+// deprecated arguments and attributes
+func Deprecations(markdown string) ([]string, []Deprecation) {
+	notes := []string{}
+	deprecations := []Deprecation{}
+
+	schema := Parse(markdown)
+	if deprecationPattern.MatchString(schema.Subcategory) || deprecationPattern.MatchString(schema.Description) {
+		notes = append(notes, joinText(schema.Subcategory, schema.Description))
+	}
+
+	inFence := false
+	for _, line := range strings.Split(markdown[frontMatterEnd(markdown):], "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "## ") {
+			break
+		}
+		if !strings.HasPrefix(trimmed, "#") && deprecationPattern.MatchString(trimmed) {
+			notes = append(notes, cleanText(strings.TrimLeft(trimmed, "~>!- ")))
+		}
+	}
+
+	for path, arg := range argumentsByPath(schema) {
+		if arg.Deprecated {
+			deprecations = append(deprecations, Deprecation{Element: "argument", Path: path, Note: arg.Description})
+		}
+	}
+	for _, attr := range schema.Attributes {
+		if attr.Deprecated {
+			path := attr.Name
+			if attr.Block != "" {
+				path = attr.Block + "." + attr.Name
+			}
+			deprecations = append(deprecations, Deprecation{Element: "attribute", Path: path, Note: attr.Description})
+		}
+	}
+	sort.Slice(deprecations, func(i, j int) bool {
+		if deprecations[i].Element != deprecations[j].Element {
+			return deprecations[i].Element < deprecations[j].Element
+		}
+		return deprecations[i].Path < deprecations[j].Path
+	})
+
+	return notes, deprecations
+}
+
+// Mentions returns the titles of the innermost sections mentioning term as a whole word
+func Mentions(markdown, term string) []string {
+	pattern := regexp.MustCompile(`\b` + regexp.QuoteMeta(term) + `\b`)
+	headings := Headings(markdown)
+
+	titles := []string{}
+	seen := map[string]bool{}
+	for _, loc := range pattern.FindAllStringIndex(markdown, -1) {
+		title := ""
+		for _, heading := range headings {
+			if heading.Offset <= loc[0] && loc[0] < heading.Offset+heading.Bytes {
+				title = heading.Title
+			}
+		}
+		if title != "" && !seen[title] {
+			seen[title] = true
+			titles = append(titles, title)
+		}
+	}
+
+	return titles
+}
@@ -0,0 +1,46 @@
+package docschema
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDeprecations(t *testing.T) {
+	doc := "# Resource: aws_s3_bucket_object\n\n" +
+		"!> **WARNING:** The aws_s3_bucket_object resource is DEPRECATED and will be removed in a future version! Use `aws_s3_object` instead.\n\n" +
+		"## Argument Reference\n\n" +
+		"* `bucket` - (Required) Name of the bucket.\n" +
+		"* `acl` - (Optional, **Deprecated**) Canned ACL.\n" +
+		"* `etag` - (Optional) Triggers updates. This argument will be removed in a future version.\n\n" +
+		"## Attribute Reference\n\n" +
+		"* `id` - The key of the resource.\n" +
+		"* `version_id` - Unique version ID. Deprecated, use `id` instead.\n"
+
+	notes, deprecations := Deprecations(doc)
+
+	wantNotes := []string{"WARNING: The aws_s3_bucket_object resource is DEPRECATED and will be removed in a future version! Use `aws_s3_object` instead."}
+	if !reflect.DeepEqual(notes, wantNotes) {
+		t.Errorf("Deprecations() notes = %q, want %q", notes, wantNotes)
+	}
+
+	paths := []string{}
+	for _, d := range deprecations {
+		paths = append(paths, d.Element+":"+d.Path)
+	}
+	wantPaths := []string{"argument:acl", "argument:etag", "attribute:version_id"}
+	if !reflect.DeepEqual(paths, wantPaths) {
+		t.Errorf("Deprecations() = %v, want %v", paths, wantPaths)
+	}
+}
+
+func TestMentions(t *testing.T) {
+	guide := "# Version 5 Upgrade Guide\n\n## Provider Arguments\n\nNothing about buckets.\n\n" +
+		"## Resource: aws_s3_bucket\n\n### Removed arguments\n\nThe `acl` argument of `aws_s3_bucket` was removed.\n\n" +
+		"## Resource: aws_s3_bucket_acl\n\nNew resource.\n"
+
+	got := Mentions(guide, "aws_s3_bucket")
+	want := []string{"Resource: aws_s3_bucket", "Removed arguments"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Mentions() = %q, want %q", got, want)
+	}
+}
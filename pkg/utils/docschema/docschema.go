@@ -0,0 +1,461 @@
+package docschema
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Schema is the structured form of a resource or data source document
+type Schema struct {
+	Name        string      `json:"name,omitempty"`
+	Subcategory string      `json:"subcategory,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Arguments   []Argument  `json:"arguments"`
+	Blocks      []Block     `json:"blocks,omitempty"`
+	Attributes  []Attribute `json:"attributes,omitempty"`
+	Timeouts    []Timeout   `json:"timeouts,omitempty"`
+	Import      *Import     `json:"import,omitempty"`
+}
+
+// Argument is an entry of the argument reference
+type Argument struct {
+	Name        string `json:"name"`
+	Required    bool   `json:"required"`
+	Type        string `json:"type,omitempty"`
+	Default     string `json:"default,omitempty"`
+	ForceNew    bool   `json:"force_new,omitempty"`
+	Deprecated  bool   `json:"deprecated,omitempty"`
+	Sensitive   bool   `json:"sensitive,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Block is a nested configuration block and the arguments it supports
+type Block struct {
+	Name      string     `json:"name"`
+	Arguments []Argument `json:"arguments"`
+}
+
+// Attribute is an entry of the attributes reference. Block is set for
+// attributes documented under a nested heading.
+type Attribute struct {
+	Name        string `json:"name"`
+	Block       string `json:"block,omitempty"`
+	Deprecated  bool   `json:"deprecated,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Timeout is a configurable operation timeout
+type Timeout struct {
+	Operation string `json:"operation"`
+	Default   string `json:"default,omitempty"`
+}
+
+// Import describes how existing infrastructure is imported
+type Import struct {
+	Description string `json:"description,omitempty"`
+	Block       string `json:"block,omitempty"`
+	Command     string `json:"command,omitempty"`
+}
+
+type section int
+
+const (
+	sectionNone section = iota
+	sectionArguments
+	sectionAttributes
+	sectionTimeouts
+	sectionImport
+)
+
+var (
+	headingPattern     = regexp.MustCompile("^(#{1,6})\\s+(.*?)\\s*#*$")
+	itemPattern        = regexp.MustCompile("^\\s*[*-]\\s+`([^`]+)`\\s*(?:[-–—:]\\s*)?(.*)$")
+	deprecationPattern = regexp.MustCompile("(?i)deprecated|will be removed|no longer (?:supported|available)")
+	forceNewPattern    = regexp.MustCompile("forces? (?:a )?new ")
+	blockIntro         = regexp.MustCompile("(?i)^(?:<a[^>]*></a>\\s*)?(?:an?|the)?\\s*`([^`]+)`\\s+(?:configuration\\s+)?block(?:s)?\\s+(?:supports|exports|contains|has)")
+	defaultsPattern    = regexp.MustCompile("(?i)defaults? to\\s+`?([^`.,;]+?)`?(?:[.,;]|$)")
+	titleNamePattern   = regexp.MustCompile("(?i)^(?:resource|data source|ephemeral resource|ephemeral|list resource)\\s*:\\s*(.+)$")
+)
+
+// Parse extracts the arguments, nested blocks, attributes, timeouts and
+// import syntax from a provider's resource or data source markdown document.
+// Sections it does not recognise are ignored.
+func Parse(markdown string) Schema {
+	schema := Schema{Arguments: []Argument{}}
+
+	lines := strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n")
+	lines = parseFrontMatter(lines, &schema)
+
+	current := sectionNone
+	block := ""
+	inFence := false
+	fence := []string{}
+	var item *Argument
+	var attribute *Attribute
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") {
+			if inFence && current == sectionImport {
+				parseImportFence(fence, &schema)
+			}
+			inFence = !inFence
+			fence = fence[:0]
+			item, attribute = nil, nil
+			continue
+		}
+		if inFence {
+			fence = append(fence, line)
+			continue
+		}
+
+		if m := headingPattern.FindStringSubmatch(trimmed); m != nil {
+			item, attribute = nil, nil
+			level, title := len(m[1]), cleanText(m[2])
+
+			switch {
+			case level == 1:
+				if schema.Name == "" {
+					if n := titleNamePattern.FindStringSubmatch(title); n != nil {
+						schema.Name = strings.TrimSpace(n[1])
+					}
+				}
+			case level == 2:
+				current, block = sectionOf(title), ""
+			case current == sectionArguments || current == sectionAttributes:
+				block = blockName(title)
+			}
+			continue
+		}
+
+		if trimmed == "" {
+			item, attribute = nil, nil
+			continue
+		}
+
+		if m := blockIntro.FindStringSubmatch(trimmed); m != nil && (current == sectionArguments || current == sectionAttributes) {
+			block = m[1]
+			item, attribute = nil, nil
+			continue
+		}
+
+		m := itemPattern.FindStringSubmatch(line)
+		if m == nil {
+			// Continuation of a wrapped list item
+			switch {
+			case item != nil:
+				item.Description = joinText(item.Description, cleanText(trimmed))
+				applyDescription(item)
+			case attribute != nil:
+				attribute.Description = joinText(attribute.Description, cleanText(trimmed))
+				attribute.Deprecated = deprecationPattern.MatchString(attribute.Description)
+			case current == sectionImport && schema.Import != nil && schema.Import.Description == "":
+				schema.Import.Description = cleanText(trimmed)
+			case current == sectionImport && schema.Import == nil:
+				schema.Import = &Import{Description: cleanText(trimmed)}
+			}
+			continue
+		}
+
+		name, rest := m[1], m[2]
+		switch current {
+		case sectionArguments:
+			item = addArgument(&schema, block, parseArgument(name, rest))
+		case sectionAttributes:
+			description := cleanText(rest)
+			schema.Attributes = append(schema.Attributes, Attribute{Name: name, Block: block, Deprecated: deprecationPattern.MatchString(description), Description: description})
+			attribute = &schema.Attributes[len(schema.Attributes)-1]
+		case sectionTimeouts:
+			schema.Timeouts = append(schema.Timeouts, parseTimeout(name, rest))
+		}
+	}
+
+	// Arguments documented with their own block section are blocks, whatever the prose says
+	blocks := map[string]bool{}
+	for _, b := range schema.Blocks {
+		blocks[b.Name] = true
+	}
+	markBlocks(schema.Arguments, blocks)
+	for i := range schema.Blocks {
+		markBlocks(schema.Blocks[i].Arguments, blocks)
+	}
+
+	return schema
+}
+
+// parseFrontMatter reads the YAML front matter keys the registry docs use and returns the remaining lines
+func parseFrontMatter(lines []string, schema *Schema) []string {
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return lines
+	}
+
+	for i := 1; i < len(lines); i++ {
+		line := lines[i]
+		if strings.TrimSpace(line) == "---" {
+			return lines[i+1:]
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || strings.HasPrefix(line, " ") {
+			continue
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+
+		switch strings.TrimSpace(key) {
+		case "subcategory":
+			schema.Subcategory = value
+		case "description":
+			if value == "|-" || value == "|" || value == ">-" || value == ">" {
+				value = ""
+				for i+1 < len(lines) && strings.HasPrefix(lines[i+1], " ") {
+					i++
+					value = joinText(value, strings.TrimSpace(lines[i]))
+				}
+			}
+			schema.Description = value
+		}
+	}
+
+	return lines
+}
+
+// sectionOf maps a second level heading to the section it starts
+func sectionOf(title string) section {
+	title = strings.ToLower(title)
+
+	switch {
+	case strings.HasPrefix(title, "argument"):
+		return sectionArguments
+	case strings.HasPrefix(title, "attribute"):
+		return sectionAttributes
+	case strings.HasPrefix(title, "timeout"):
+		return sectionTimeouts
+	case strings.HasPrefix(title, "import"):
+		return sectionImport
+	}
+	return sectionNone
+}
+
+// blockName turns a nested heading ("`rule` Configuration Block", "Rule Arguments") into a block name
+func blockName(title string) string {
+	name := strings.ToLower(title)
+	for _, suffix := range []string{" arguments", " argument reference", " configuration block", " block", " configuration"} {
+		name = strings.TrimSuffix(name, suffix)
+	}
+	name = strings.Trim(strings.TrimSpace(name), "`")
+
+	// "### Required" and "### Optional" group top-level arguments rather than starting a block
+	if name == "required" || name == "optional" || name == "argument reference" {
+		return ""
+	}
+	return strings.ReplaceAll(name, " ", "_")
+}
+
+func addArgument(schema *Schema, block string, arg Argument) *Argument {
+	if block == "" {
+		schema.Arguments = append(schema.Arguments, arg)
+		return &schema.Arguments[len(schema.Arguments)-1]
+	}
+
+	for i := range schema.Blocks {
+		if schema.Blocks[i].Name == block {
+			schema.Blocks[i].Arguments = append(schema.Blocks[i].Arguments, arg)
+			return &schema.Blocks[i].Arguments[len(schema.Blocks[i].Arguments)-1]
+		}
+	}
+
+	schema.Blocks = append(schema.Blocks, Block{Name: block, Arguments: []Argument{arg}})
+	b := &schema.Blocks[len(schema.Blocks)-1]
+	return &b.Arguments[0]
+}
+
+// parseArgument reads the "(Required, Forces new resource)" annotation and the description of an item
+func parseArgument(name, rest string) Argument {
+	arg := Argument{Name: name}
+
+	annotation, description := splitAnnotation(rest)
+	for _, part := range strings.Split(annotation, ",") {
+		part = strings.ToLower(cleanText(part))
+
+		switch {
+		case part == "required":
+			arg.Required = true
+		case strings.HasPrefix(part, "default"):
+			arg.Default = defaultValue(part)
+		case strings.Contains(part, "forces new"):
+			arg.ForceNew = true
+		case strings.Contains(part, "deprecated"):
+			arg.Deprecated = true
+		case strings.Contains(part, "sensitive"):
+			arg.Sensitive = true
+		case isTypeHint(part):
+			arg.Type = typeHint(part)
+		}
+	}
+
+	arg.Description = cleanText(description)
+	applyDescription(&arg)
+
+	return arg
+}
+
+// applyDescription fills in what the description states in prose
+func applyDescription(arg *Argument) {
+	lower := strings.ToLower(arg.Description)
+
+	if forceNewPattern.MatchString(lower) {
+		arg.ForceNew = true
+	}
+	if deprecationPattern.MatchString(lower) {
+		arg.Deprecated = true
+	}
+	if arg.Default == "" {
+		if m := defaultsPattern.FindStringSubmatch(arg.Description); m != nil {
+			arg.Default = strings.TrimSpace(m[1])
+		}
+	}
+	if arg.Type == "" {
+		arg.Type = typeFromDescription(lower)
+	}
+}
+
+func parseTimeout(name, rest string) Timeout {
+	timeout := Timeout{Operation: name}
+
+	annotation, description := splitAnnotation(rest)
+	if annotation == "" {
+		annotation = description
+	}
+	if m := defaultsPattern.FindStringSubmatch(cleanText(annotation)); m != nil {
+		timeout.Default = strings.TrimSpace(m[1])
+	} else if strings.HasPrefix(strings.ToLower(annotation), "default") {
+		timeout.Default = defaultValue(strings.ToLower(cleanText(annotation)))
+	}
+
+	return timeout
+}
+
+// parseImportFence keeps the first import block and terraform import command of the import section
+func parseImportFence(fence []string, schema *Schema) {
+	if schema.Import == nil {
+		schema.Import = &Import{}
+	}
+
+	code := strings.TrimSpace(strings.Join(fence, "\n"))
+	switch {
+	case strings.HasPrefix(code, "import {") && schema.Import.Block == "":
+		schema.Import.Block = code
+	case schema.Import.Command == "":
+		for _, line := range fence {
+			line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "%$"))
+			if strings.HasPrefix(line, "terraform import") {
+				schema.Import.Command = line
+				break
+			}
+		}
+	}
+}
+
+// splitAnnotation separates a leading parenthesised annotation, which may contain parentheses itself
+func splitAnnotation(text string) (string, string) {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "(") {
+		return "", text
+	}
+
+	depth := 0
+	for i, r := range text {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return text[1:i], strings.TrimSpace(text[i+1:])
+			}
+		}
+	}
+	return "", text
+}
+
+// defaultValue reads the value out of "default: `false`", "default `20m`" or "defaults to 30 minutes"
+func defaultValue(part string) string {
+	part = strings.TrimPrefix(part, "defaults to")
+	part = strings.TrimPrefix(part, "defaults")
+	part = strings.TrimPrefix(part, "default")
+	return strings.TrimSpace(strings.Trim(strings.TrimSpace(part), "`:= "))
+}
+
+var typeHints = map[string]string{
+	"string":  "string",
+	"bool":    "bool",
+	"boolean": "bool",
+	"number":  "number",
+	"int":     "number",
+	"integer": "number",
+	"float":   "number",
+	"list":    "list",
+	"set":     "set",
+	"map":     "map",
+	"block":   "block",
+}
+
+func isTypeHint(part string) bool {
+	return typeHint(part) != ""
+}
+
+func typeHint(part string) string {
+	first, _, _ := strings.Cut(part, " ")
+	if strings.HasPrefix(part, "configuration block") {
+		return "block"
+	}
+	return typeHints[first]
+}
+
+// typeFromDescription guesses the type from the usual wording of provider docs
+func typeFromDescription(lower string) string {
+	switch {
+	case strings.HasPrefix(lower, "configuration block"), strings.Contains(lower, "block as defined below"), strings.Contains(lower, "block as documented below"):
+		return "block"
+	case strings.HasPrefix(lower, "list of"), strings.HasPrefix(lower, "a list of"):
+		return "list"
+	case strings.HasPrefix(lower, "set of"), strings.HasPrefix(lower, "a set of"):
+		return "set"
+	case strings.HasPrefix(lower, "map of"), strings.HasPrefix(lower, "a map of"), strings.HasPrefix(lower, "key-value map"), strings.HasPrefix(lower, "a mapping of"):
+		return "map"
+	case strings.HasPrefix(lower, "boolean"), strings.HasPrefix(lower, "whether"), strings.HasPrefix(lower, "indicates whether"):
+		return "bool"
+	case strings.HasPrefix(lower, "number of"), strings.HasPrefix(lower, "the number of"):
+		return "number"
+	}
+	return ""
+}
+
+func markBlocks(args []Argument, blocks map[string]bool) {
+	for i := range args {
+		if blocks[args[i].Name] {
+			args[i].Type = "block"
+		}
+	}
+}
+
+// cleanText drops markdown emphasis, links and anchors from text
+func cleanText(text string) string {
+	text = linkPattern.ReplaceAllString(text, "$1")
+	text = anchorPattern.ReplaceAllString(text, "")
+	text = strings.NewReplacer("**", "", "__", "").Replace(text)
+	return strings.TrimSpace(text)
+}
+
+var (
+	linkPattern   = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	anchorPattern = regexp.MustCompile(`<a[^>]*>\s*</a>`)
+)
+
+func joinText(a, b string) string {
+	if a == "" {
+		return b
+	}
+	return a + " " + b
+}
@@ -0,0 +1,165 @@
+package docschema
+
+import (
+	"reflect"
+	"testing"
+)
+
+const awsDoc = "---\n" +
+	"subcategory: \"S3 (Simple Storage)\"\n" +
+	"layout: \"aws\"\n" +
+	"page_title: \"AWS: aws_s3_bucket\"\n" +
+	"description: |-\n" +
+	"  Provides a S3 bucket resource.\n" +
+	"---\n" +
+	"\n" +
+	"# Resource: aws_s3_bucket\n" +
+	"\n" +
+	"Provides a S3 bucket resource.\n" +
+	"\n" +
+	"## Example Usage\n" +
+	"\n" +
+	"```terraform\n" +
+	"resource \"aws_s3_bucket\" \"example\" {\n" +
+	"  bucket = \"my-tf-test-bucket\"\n" +
+	"}\n" +
+	"```\n" +
+	"\n" +
+	"## Argument Reference\n" +
+	"\n" +
+	"This resource supports the following arguments:\n" +
+	"\n" +
+	"* `bucket` - (Optional, Forces new resource) Name of the bucket.\n" +
+	"* `force_destroy` - (Optional, Default:`false`) Boolean that indicates all objects should be deleted\n" +
+	"  from the bucket when the bucket is destroyed.\n" +
+	"* `object_lock_configuration` - (Optional, **Deprecated**) Configuration of [S3 object locking](https://docs.aws.amazon.com). See below.\n" +
+	"* `tags` - (Optional) Map of tags to assign to the bucket.\n" +
+	"\n" +
+	"### object_lock_configuration\n" +
+	"\n" +
+	"* `object_lock_enabled` - (Optional, Forces new resource) Indicates whether this bucket has an Object Lock configuration enabled. Defaults to `Enabled`.\n" +
+	"\n" +
+	"## Attribute Reference\n" +
+	"\n" +
+	"This resource exports the following attributes in addition to the arguments above:\n" +
+	"\n" +
+	"* `id` - Name of the bucket.\n" +
+	"* `arn` - ARN of the bucket.\n" +
+	"\n" +
+	"## Timeouts\n" +
+	"\n" +
+	"- `create` - (Default `20m`)\n" +
+	"- `delete` - (Default `60m`)\n" +
+	"\n" +
+	"## Import\n" +
+	"\n" +
+	"In Terraform v1.5.0 and later, use an `import` block to import S3 bucket using the `bucket`. For example:\n" +
+	"\n" +
+	"```terraform\n" +
+	"import {\n" +
+	"  to = aws_s3_bucket.bucket\n" +
+	"  id = \"bucket-name\"\n" +
+	"}\n" +
+	"```\n" +
+	"\n" +
+	"```console\n" +
+	"% terraform import aws_s3_bucket.bucket bucket-name\n" +
+	"```\n"
+
+const azurermDoc = "# azurerm_linux_web_app\n" +
+	"\n" +
+	"## Arguments Reference\n" +
+	"\n" +
+	"* `name` - (Required) The name which should be used for this Linux Web App. Changing this forces a new Linux Web App to be created.\n" +
+	"\n" +
+	"* `site_config` - (Required) A `site_config` block as defined below.\n" +
+	"\n" +
+	"---\n" +
+	"\n" +
+	"A `site_config` block supports the following:\n" +
+	"\n" +
+	"* `always_on` - (Optional) If this Linux Web App is Always On enabled. Defaults to `true`.\n" +
+	"\n" +
+	"## Attributes Reference\n" +
+	"\n" +
+	"* `id` - The ID of the Linux Web App.\n" +
+	"\n" +
+	"---\n" +
+	"\n" +
+	"A `identity` block exports the following:\n" +
+	"\n" +
+	"* `principal_id` - The Principal ID associated with this Managed Service Identity.\n" +
+	"\n" +
+	"## Timeouts\n" +
+	"\n" +
+	"* `create` - (Defaults to 30 minutes) Used when creating the Linux Web App.\n"
+
+func TestParse_Aws(t *testing.T) {
+	schema := Parse(awsDoc)
+
+	if schema.Name != "aws_s3_bucket" || schema.Subcategory != "S3 (Simple Storage)" || schema.Description != "Provides a S3 bucket resource." {
+		t.Errorf("Parse() header = %q, %q, %q", schema.Name, schema.Subcategory, schema.Description)
+	}
+
+	wantArgs := []Argument{
+		{Name: "bucket", ForceNew: true, Description: "Name of the bucket."},
+		{Name: "force_destroy", Type: "bool", Default: "false", Description: "Boolean that indicates all objects should be deleted from the bucket when the bucket is destroyed."},
+		{Name: "object_lock_configuration", Type: "block", Deprecated: true, Description: "Configuration of S3 object locking. See below."},
+		{Name: "tags", Type: "map", Description: "Map of tags to assign to the bucket."},
+	}
+	if !reflect.DeepEqual(schema.Arguments, wantArgs) {
+		t.Errorf("Parse() arguments = %+v, want %+v", schema.Arguments, wantArgs)
+	}
+
+	wantBlocks := []Block{{Name: "object_lock_configuration", Arguments: []Argument{
+		{Name: "object_lock_enabled", Type: "bool", Default: "Enabled", ForceNew: true, Description: "Indicates whether this bucket has an Object Lock configuration enabled. Defaults to `Enabled`."},
+	}}}
+	if !reflect.DeepEqual(schema.Blocks, wantBlocks) {
+		t.Errorf("Parse() blocks = %+v, want %+v", schema.Blocks, wantBlocks)
+	}
+
+	if len(schema.Attributes) != 2 || schema.Attributes[1].Name != "arn" {
+		t.Errorf("Parse() attributes = %+v", schema.Attributes)
+	}
+
+	wantTimeouts := []Timeout{{Operation: "create", Default: "20m"}, {Operation: "delete", Default: "60m"}}
+	if !reflect.DeepEqual(schema.Timeouts, wantTimeouts) {
+		t.Errorf("Parse() timeouts = %+v, want %+v", schema.Timeouts, wantTimeouts)
+	}
+
+	if schema.Import == nil {
+		t.Fatal("Parse() import = nil")
+	}
+	if schema.Import.Command != "terraform import aws_s3_bucket.bucket bucket-name" {
+		t.Errorf("Parse() import command = %q", schema.Import.Command)
+	}
+	if schema.Import.Block != "import {\n  to = aws_s3_bucket.bucket\n  id = \"bucket-name\"\n}" {
+		t.Errorf("Parse() import block = %q", schema.Import.Block)
+	}
+}
+
+func TestParse_Azurerm(t *testing.T) {
+	schema := Parse(azurermDoc)
+
+	if len(schema.Arguments) != 2 {
+		t.Fatalf("Parse() arguments = %+v", schema.Arguments)
+	}
+	if name := schema.Arguments[0]; !name.Required || !name.ForceNew {
+		t.Errorf("Parse() name = %+v, want required and force_new", name)
+	}
+	if siteConfig := schema.Arguments[1]; siteConfig.Type != "block" {
+		t.Errorf("Parse() site_config type = %q, want block", siteConfig.Type)
+	}
+
+	if len(schema.Blocks) != 1 || schema.Blocks[0].Name != "site_config" || schema.Blocks[0].Arguments[0].Default != "true" {
+		t.Errorf("Parse() blocks = %+v", schema.Blocks)
+	}
+
+	if len(schema.Attributes) != 2 || schema.Attributes[1].Block != "identity" {
+		t.Errorf("Parse() attributes = %+v", schema.Attributes)
+	}
+
+	if len(schema.Timeouts) != 1 || schema.Timeouts[0].Default != "30 minutes" {
+		t.Errorf("Parse() timeouts = %+v", schema.Timeouts)
+	}
+}
@@ -0,0 +1,86 @@
+package docschema
+
+import (
+	"strings"
+
+	"github.com/Yunsang-Jeong/terraform-mcp-server/pkg/utils"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// exampleLanguages are the fence languages provider docs use for configuration
+var exampleLanguages = []string{"terraform", "hcl", "tf"}
+
+// Example is a fenced configuration snippet of a document
+type Example struct {
+	// Section is the second level heading the example is under, e.g. "Example Usage"
+	Section string `json:"section,omitempty"`
+	// Heading is the closest heading above the example
+	Heading  string `json:"heading,omitempty"`
+	Language string `json:"language"`
+	Code     string `json:"code"`
+	Valid    bool   `json:"valid"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Examples returns every terraform/hcl fenced block of a document with the
+// headings it is under, checking that each one parses as HCL
+func Examples(markdown string) []Example {
+	examples := []Example{}
+
+	section, heading := "", ""
+	inFence, language := false, ""
+	code := []string{}
+
+	offset := frontMatterEnd(markdown)
+	for _, line := range strings.SplitAfter(markdown[offset:], "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") {
+			if inFence {
+				if utils.IsInList(language, exampleLanguages) {
+					examples = append(examples, newExample(section, heading, language, strings.Join(code, "")))
+				}
+				inFence, code = false, code[:0]
+			} else {
+				inFence, language = true, strings.ToLower(strings.TrimSpace(strings.TrimPrefix(trimmed, "```")))
+			}
+			continue
+		}
+		if inFence {
+			code = append(code, line)
+			continue
+		}
+
+		if m := headingPattern.FindStringSubmatch(trimmed); m != nil {
+			heading = cleanText(m[2])
+			switch len(m[1]) {
+			case 1:
+				section = ""
+			case 2:
+				section = heading
+			}
+		}
+	}
+
+	return examples
+}
+
+func newExample(section, heading, language, code string) Example {
+	example := Example{
+		Section:  section,
+		Heading:  heading,
+		Language: language,
+		Code:     strings.TrimRight(code, "\n"),
+		Valid:    true,
+	}
+
+	_, diags := hclsyntax.ParseConfig([]byte(code), "example.tf", hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		example.Valid = false
+		example.Error = diags.Error()
+	}
+
+	return example
+}
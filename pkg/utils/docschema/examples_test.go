@@ -0,0 +1,31 @@
+package docschema
+
+import (
+	"testing"
+)
+
+func TestExamples(t *testing.T) {
+	doc := awsDoc +
+		"\n## Example Usage With Errors\n\n" +
+		"```hcl\nresource \"aws_s3_bucket\" \"broken\" {\n  bucket = \n```\n" +
+		"\n```json\n{\"not\": \"hcl\"}\n```\n"
+
+	examples := Examples(doc)
+
+	if len(examples) != 3 {
+		t.Fatalf("Examples() = %d examples, want 3: %+v", len(examples), examples)
+	}
+
+	if e := examples[0]; e.Section != "Example Usage" || e.Language != "terraform" || !e.Valid || e.Code != "resource \"aws_s3_bucket\" \"example\" {\n  bucket = \"my-tf-test-bucket\"\n}" {
+		t.Errorf("Examples()[0] = %+v", e)
+	}
+
+	// The import block is an example too, labelled by its section
+	if e := examples[1]; e.Section != "Import" || !e.Valid {
+		t.Errorf("Examples()[1] = %+v", e)
+	}
+
+	if e := examples[2]; e.Section != "Example Usage With Errors" || e.Valid || e.Error == "" {
+		t.Errorf("Examples()[2] = %+v, want a parse error", e)
+	}
+}
@@ -0,0 +1,213 @@
+package docschema
+
+import (
+	"fmt"
+	"hash/crc32"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Heading is a section of a document: a markdown heading, or a nested block
+// introduced by a paragraph such as "A `site_config` block supports the following:".
+// Bytes covers the section and its subsections.
+type Heading struct {
+	Level  int    `json:"level"`
+	Title  string `json:"title"`
+	Offset int    `json:"offset"`
+	Bytes  int    `json:"bytes"`
+}
+
+// Page is a part of a document returned by Chunk
+type Page struct {
+	Content string
+	Start   int
+	End     int
+	Total   int
+	// Cursor continues after End, empty on the last page
+	Cursor string
+}
+
+// Headings returns the sections of a markdown document in order, skipping
+// front matter and fenced code
+func Headings(markdown string) []Heading {
+	headings := []Heading{}
+	rules := []int{}
+	intro := map[int]bool{}
+
+	level := 1
+	inFence := false
+	offset := frontMatterEnd(markdown)
+	for offset < len(markdown) {
+		line := markdown[offset:]
+		if i := strings.IndexByte(line, '\n'); i >= 0 {
+			line = line[:i+1]
+		}
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case strings.HasPrefix(trimmed, "```"):
+			inFence = !inFence
+		case inFence:
+		case trimmed == "---":
+			rules = append(rules, offset)
+		default:
+			if m := headingPattern.FindStringSubmatch(trimmed); m != nil {
+				level = len(m[1])
+				headings = append(headings, Heading{Level: level, Title: cleanText(m[2]), Offset: offset})
+			} else if m := blockIntro.FindStringSubmatch(trimmed); m != nil {
+				intro[len(headings)] = true
+				headings = append(headings, Heading{Level: level + 1, Title: m[1] + " block", Offset: offset})
+			}
+		}
+
+		offset += len(line)
+	}
+
+	for i := range headings {
+		end := len(markdown)
+		for _, next := range headings[i+1:] {
+			if next.Level <= headings[i].Level || (intro[i] && next.Offset > headings[i].Offset) {
+				end = next.Offset
+				break
+			}
+		}
+		// A block introduced by a paragraph runs until the next horizontal rule
+		if intro[i] {
+			for _, rule := range rules {
+				if rule > headings[i].Offset && rule < end {
+					end = rule
+					break
+				}
+			}
+		}
+		headings[i].Bytes = end - headings[i].Offset
+	}
+
+	return headings
+}
+
+// Select returns the sections whose title matches one of names, in document
+// order. Names are matched case-insensitively and without markdown, so
+// "argument reference", "Arguments Reference" and "object_lock_configuration"
+// all find their section.
+func Select(markdown string, names []string) (string, error) {
+	headings := Headings(markdown)
+
+	selected := map[int]bool{}
+	for _, name := range names {
+		found := false
+		for i, heading := range headings {
+			if sectionKey(heading.Title) == sectionKey(name) {
+				selected[i] = true
+				found = true
+			}
+		}
+		if !found {
+			titles := []string{}
+			for _, heading := range headings {
+				titles = append(titles, heading.Title)
+			}
+			return "", fmt.Errorf("section not found: %s; available sections: %s", name, strings.Join(titles, ", "))
+		}
+	}
+
+	parts := []string{}
+	covered := 0
+	for i, heading := range headings {
+		// Skip sections already included as part of a selected parent
+		if !selected[i] || heading.Offset < covered {
+			continue
+		}
+		end := heading.Offset + heading.Bytes
+		parts = append(parts, strings.TrimRight(markdown[heading.Offset:end], "\n"))
+		covered = end
+	}
+
+	return strings.Join(parts, "\n\n") + "\n", nil
+}
+
+// Chunk returns the page of markdown starting at cursor, at most maxBytes
+// long. Pages end at a heading when one falls in the second half of the page,
+// otherwise at a line break. A maxBytes of zero returns the rest of the document.
+func Chunk(markdown string, maxBytes int, cursor string) (Page, error) {
+	start, err := parseCursor(markdown, cursor)
+	if err != nil {
+		return Page{}, err
+	}
+
+	page := Page{Start: start, End: len(markdown), Total: len(markdown)}
+	if maxBytes > 0 && start+maxBytes < len(markdown) {
+		page.End = pageEnd(markdown, start, start+maxBytes)
+		page.Cursor = newCursor(markdown, page.End)
+	}
+	page.Content = markdown[page.Start:page.End]
+
+	return page, nil
+}
+
+func pageEnd(markdown string, start, limit int) int {
+	headings := Headings(markdown)
+	for i := len(headings) - 1; i >= 0; i-- {
+		offset := headings[i].Offset
+		if offset <= limit && offset > start+(limit-start)/2 {
+			return offset
+		}
+	}
+
+	if i := strings.LastIndexByte(markdown[start:limit], '\n'); i >= 0 {
+		return start + i + 1
+	}
+
+	end := limit
+	for end > start+1 && !utf8.RuneStart(markdown[end]) {
+		end--
+	}
+	return end
+}
+
+// newCursor encodes an offset with a checksum of the document, so a cursor
+// is not applied to a document that changed in between
+func newCursor(markdown string, offset int) string {
+	return fmt.Sprintf("%d-%08x", offset, crc32.ChecksumIEEE([]byte(markdown)))
+}
+
+func parseCursor(markdown, cursor string) (int, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+
+	offsetText, _, _ := strings.Cut(cursor, "-")
+	offset, err := strconv.Atoi(offsetText)
+	if err != nil || offset < 0 || offset > len(markdown) {
+		return 0, fmt.Errorf("invalid cursor: %s", cursor)
+	}
+	if newCursor(markdown, offset) != cursor {
+		return 0, fmt.Errorf("document changed since cursor %s was issued; start again without a cursor", cursor)
+	}
+
+	return offset, nil
+}
+
+// frontMatterEnd returns the offset right after the YAML front matter, or 0 without one
+func frontMatterEnd(markdown string) int {
+	if !strings.HasPrefix(markdown, "---\n") {
+		return 0
+	}
+
+	if i := strings.Index(markdown[4:], "\n---\n"); i >= 0 {
+		return 4 + i + len("\n---\n")
+	}
+	return 0
+}
+
+// sectionKey is the form section titles and requested names are compared in
+func sectionKey(title string) string {
+	key := strings.ToLower(cleanText(title))
+	key = strings.Join(strings.Fields(strings.ReplaceAll(key, "`", "")), " ")
+
+	for _, suffix := range []string{" configuration block", " block"} {
+		key = strings.TrimSuffix(key, suffix)
+	}
+	return strings.NewReplacer("arguments reference", "argument reference", "attributes reference", "attribute reference").Replace(key)
+}
@@ -0,0 +1,109 @@
+package docschema
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHeadings(t *testing.T) {
+	headings := Headings(azurermDoc)
+
+	want := []struct {
+		level int
+		title string
+	}{
+		{1, "azurerm_linux_web_app"},
+		{2, "Arguments Reference"},
+		{3, "site_config block"},
+		{2, "Attributes Reference"},
+		{3, "identity block"},
+		{2, "Timeouts"},
+	}
+	if len(headings) != len(want) {
+		t.Fatalf("Headings() = %+v", headings)
+	}
+	for i, w := range want {
+		if headings[i].Level != w.level || headings[i].Title != w.title {
+			t.Errorf("Headings()[%d] = %d %q, want %d %q", i, headings[i].Level, headings[i].Title, w.level, w.title)
+		}
+	}
+
+	siteConfig := azurermDoc[headings[2].Offset : headings[2].Offset+headings[2].Bytes]
+	if !strings.HasPrefix(siteConfig, "A `site_config` block") || !strings.HasSuffix(siteConfig, "Defaults to `true`.\n\n") {
+		t.Errorf("Headings() site_config section = %q", siteConfig)
+	}
+}
+
+func TestHeadings_SkipsFrontMatterAndCode(t *testing.T) {
+	for _, heading := range Headings(awsDoc) {
+		if heading.Offset < strings.Index(awsDoc, "# Resource") {
+			t.Errorf("Headings() found %q in front matter", heading.Title)
+		}
+		if strings.HasPrefix(heading.Title, "resource \"") {
+			t.Errorf("Headings() found %q in fenced code", heading.Title)
+		}
+	}
+}
+
+func TestSelect(t *testing.T) {
+	got, err := Select(awsDoc, []string{"import", "argument reference", "object_lock_configuration"})
+	if err != nil {
+		t.Fatalf("Select() unexpected error: %v", err)
+	}
+
+	// object_lock_configuration is part of the argument reference, so it is not repeated
+	if !strings.HasPrefix(got, "## Argument Reference") || strings.Count(got, "### object_lock_configuration") != 1 {
+		t.Errorf("Select() = %q", got)
+	}
+	if !strings.Contains(got, "## Import") || strings.Contains(got, "## Timeouts") {
+		t.Errorf("Select() = %q", got)
+	}
+
+	if _, err := Select(awsDoc, []string{"Usage Notes"}); err == nil || !strings.Contains(err.Error(), "available sections") {
+		t.Errorf("Select() error = %v, want available sections", err)
+	}
+}
+
+func TestChunk(t *testing.T) {
+	var pages []string
+	cursor := ""
+	for {
+		page, err := Chunk(awsDoc, 300, cursor)
+		if err != nil {
+			t.Fatalf("Chunk() unexpected error: %v", err)
+		}
+		if len(page.Content) > 300 {
+			t.Errorf("Chunk() page of %d bytes, want at most 300", len(page.Content))
+		}
+		pages = append(pages, page.Content)
+
+		if page.Cursor == "" {
+			break
+		}
+		cursor = page.Cursor
+	}
+
+	if got := strings.Join(pages, ""); got != awsDoc {
+		t.Errorf("Chunk() pages do not add up to the document")
+	}
+	if len(pages) < 2 {
+		t.Errorf("Chunk() = %d pages, want several", len(pages))
+	}
+}
+
+func TestChunk_InvalidCursor(t *testing.T) {
+	page, err := Chunk(awsDoc, 300, "")
+	if err != nil {
+		t.Fatalf("Chunk() unexpected error: %v", err)
+	}
+
+	for _, cursor := range []string{"abc", "99999-00000000"} {
+		if _, err := Chunk(awsDoc, 300, cursor); err == nil {
+			t.Errorf("Chunk(%q) expected error", cursor)
+		}
+	}
+
+	if _, err := Chunk(azurermDoc, 300, page.Cursor); err == nil {
+		t.Errorf("Chunk() expected error for a cursor of another document")
+	}
+}
@@ -0,0 +1,204 @@
+package docsearch
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/Yunsang-Jeong/terraform-mcp-server/pkg/utils"
+	"github.com/Yunsang-Jeong/terraform-mcp-server/pkg/utils/registry"
+)
+
+const (
+	// MIN_SUGGESTION_SCORE is the lowest score worth offering as "did you mean"
+	MIN_SUGGESTION_SCORE = 0.5
+)
+
+// synonyms maps words agents commonly use to the words providers use in slugs
+var synonyms = map[string][]string{
+	"vm":       {"virtual", "machine", "instance"},
+	"ec2":      {"instance"},
+	"sg":       {"security", "group"},
+	"rg":       {"resource", "group"},
+	"db":       {"database", "db"},
+	"k8s":      {"kubernetes"},
+	"aks":      {"kubernetes", "cluster"},
+	"gke":      {"container", "cluster"},
+	"lb":       {"lb", "load", "balancer"},
+	"alb":      {"lb"},
+	"elb":      {"lb", "elb"},
+	"vnet":     {"virtual", "network"},
+	"subnets":  {"subnet"},
+	"buckets":  {"bucket"},
+	"policies": {"policy"},
+	"doc":      {"document"},
+	"docs":     {"document"},
+	"kv":       {"key", "vault"},
+	"func":     {"function"},
+	"lambda":   {"lambda", "function"},
+}
+
+// Match is a document ranked against a query
+type Match struct {
+	registry.ProviderDoc
+	Score float64 `json:"score"`
+}
+
+// Normalize turns a name as an agent might write it ("aws_s3_bucket",
+// "S3 Bucket", "s3-bucket", "provider::aws::arn_parse") into the slug form
+// used by the registry ("s3_bucket", "arn_parse")
+func Normalize(providerName, name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = strings.TrimPrefix(name, "provider::"+strings.ToLower(providerName)+"::")
+	name = strings.NewReplacer(" ", "_", "-", "_", ".", "_").Replace(name)
+	name = strings.Trim(name, "_")
+
+	prefix := strings.ToLower(providerName) + "_"
+	if providerName != "" && strings.HasPrefix(name, prefix) && len(name) > len(prefix) {
+		name = strings.TrimPrefix(name, prefix)
+	}
+
+	return name
+}
+
+// Find returns the document whose slug or title matches name exactly, after normalization
+func Find(docs []registry.ProviderDoc, providerName, name string) (registry.ProviderDoc, bool) {
+	for _, doc := range docs {
+		if doc.Slug == name {
+			return doc, true
+		}
+	}
+
+	normalized := Normalize(providerName, name)
+	for _, doc := range docs {
+		if Normalize(providerName, doc.Slug) == normalized || Normalize(providerName, doc.Title) == normalized {
+			return doc, true
+		}
+	}
+
+	return registry.ProviderDoc{}, false
+}
+
+// Search ranks documents by how well their slug or title matches the query,
+// tolerating provider prefixes, typos and common synonyms. At most limit
+// matches scoring at least minScore are returned, best first.
+func Search(docs []registry.ProviderDoc, providerName, query string, minScore float64, limit int) []Match {
+	normalized := Normalize(providerName, query)
+	if normalized == "" {
+		return []Match{}
+	}
+	queryTokens := expandTokens(tokenize(normalized))
+
+	matches := []Match{}
+	for _, doc := range docs {
+		score := 0.0
+		for _, candidate := range []string{doc.Slug, doc.Title} {
+			if candidate == "" {
+				continue
+			}
+			score = max(score, similarity(normalized, queryTokens, Normalize(providerName, candidate)))
+		}
+
+		if score >= minScore {
+			matches = append(matches, Match{ProviderDoc: doc, Score: round(score)})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].Slug < matches[j].Slug
+	})
+
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	return matches
+}
+
+// similarity scores a candidate between 0 and 1
+func similarity(query string, queryTokens []string, candidate string) float64 {
+	if query == candidate {
+		return 1
+	}
+
+	// Whole-string edit distance catches typos such as "s3_bukcet"
+	editScore := 1 - float64(utils.Levenshtein(query, candidate))/float64(max(len(query), len(candidate)))
+
+	// Token overlap catches reordered or partial names such as "bucket_policy_s3"
+	tokenScore := tokenOverlap(queryTokens, expandTokens(tokenize(candidate)))
+
+	score := max(editScore, 0.95*tokenScore)
+
+	// A candidate containing the whole query (or the reverse) is a strong hint
+	if strings.Contains(candidate, query) || strings.Contains(query, candidate) {
+		score += 0.1
+	}
+
+	return min(score, 0.99)
+}
+
+// tokenOverlap is the Jaccard similarity of two token sets, treating tokens
+// one edit apart as equal
+func tokenOverlap(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	matched := 0
+	for _, ta := range a {
+		for _, tb := range b {
+			if tokensEqual(ta, tb) {
+				matched++
+				break
+			}
+		}
+	}
+
+	union := len(a) + len(b) - matched
+	return float64(matched) / float64(union)
+}
+
+func tokensEqual(a, b string) bool {
+	if a == b {
+		return true
+	}
+	if len(a) < 4 || len(b) < 4 {
+		return false
+	}
+	return utils.Levenshtein(a, b) <= 1
+}
+
+func tokenize(s string) []string {
+	tokens := []string{}
+	for _, token := range strings.Split(s, "_") {
+		if token != "" {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
+
+// expandTokens replaces known synonyms, keeping each token once
+func expandTokens(tokens []string) []string {
+	seen := map[string]bool{}
+	expanded := []string{}
+	for _, token := range tokens {
+		replacements, ok := synonyms[token]
+		if !ok {
+			replacements = []string{token}
+		}
+		for _, r := range replacements {
+			if !seen[r] {
+				seen[r] = true
+				expanded = append(expanded, r)
+			}
+		}
+	}
+	return expanded
+}
+
+func round(f float64) float64 {
+	return float64(int(f*1000+0.5)) / 1000
+}
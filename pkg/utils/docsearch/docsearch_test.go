@@ -0,0 +1,95 @@
+package docsearch
+
+import (
+	"testing"
+
+	"github.com/Yunsang-Jeong/terraform-mcp-server/pkg/utils/registry"
+)
+
+var testDocs = []registry.ProviderDoc{
+	{ID: "1", Category: "resources", Slug: "s3_bucket", Title: "aws_s3_bucket"},
+	{ID: "2", Category: "resources", Slug: "s3_bucket_policy", Title: "aws_s3_bucket_policy"},
+	{ID: "3", Category: "data-sources", Slug: "iam_policy_document", Title: "aws_iam_policy_document"},
+	{ID: "4", Category: "resources", Slug: "instance", Title: "aws_instance"},
+	{ID: "5", Category: "resources", Slug: "security_group", Title: "aws_security_group"},
+	{ID: "6", Category: "resources", Slug: "lb_listener", Title: "aws_lb_listener"},
+	{ID: "7", Category: "resources", Slug: "s3_bucket_versioning", Title: "aws_s3_bucket_versioning"},
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "aws_s3_bucket", want: "s3_bucket"},
+		{name: "S3 Bucket", want: "s3_bucket"},
+		{name: "s3-bucket", want: "s3_bucket"},
+		{name: "aws", want: "aws"},
+		{name: "  instance ", want: "instance"},
+		{name: "provider::aws::arn_parse", want: "arn_parse"},
+	}
+
+	for _, tt := range tests {
+		if got := Normalize("aws", tt.name); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFind(t *testing.T) {
+	tests := []struct {
+		name   string
+		wantID string
+		found  bool
+	}{
+		{name: "s3_bucket", wantID: "1", found: true},
+		{name: "aws_s3_bucket", wantID: "1", found: true},
+		{name: "AWS_Security_Group", wantID: "5", found: true},
+		{name: "s3_bukcet", found: false},
+	}
+
+	for _, tt := range tests {
+		doc, found := Find(testDocs, "aws", tt.name)
+		if found != tt.found || doc.ID != tt.wantID {
+			t.Errorf("Find(%q) = %q, %v; want %q, %v", tt.name, doc.ID, found, tt.wantID, tt.found)
+		}
+	}
+}
+
+func TestSearch(t *testing.T) {
+	tests := []struct {
+		query  string
+		wantID string
+	}{
+		{query: "s3_bukcet", wantID: "1"},
+		{query: "aws_s3_bucket_polcy", wantID: "2"},
+		{query: "s3_bucket_policy_document", wantID: "2"},
+		{query: "policy_document", wantID: "3"},
+		{query: "sg", wantID: "5"},
+		{query: "ec2", wantID: "4"},
+		{query: "bucket versioning", wantID: "7"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			matches := Search(testDocs, "aws", tt.query, 0.3, 3)
+			if len(matches) == 0 {
+				t.Fatalf("Search(%q) returned no matches", tt.query)
+			}
+			if matches[0].ID != tt.wantID {
+				t.Errorf("Search(%q) best match = %s (%v), want %s; all: %+v", tt.query, matches[0].Slug, matches[0].Score, tt.wantID, matches)
+			}
+		})
+	}
+}
+
+func TestSearch_Limit(t *testing.T) {
+	matches := Search(testDocs, "aws", "s3", 0, 2)
+	if len(matches) != 2 {
+		t.Errorf("Search() returned %d matches, want 2", len(matches))
+	}
+
+	if matches := Search(testDocs, "aws", "", 0, 0); len(matches) != 0 {
+		t.Errorf("Search() with empty query returned %d matches", len(matches))
+	}
+}
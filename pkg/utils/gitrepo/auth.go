@@ -0,0 +1,289 @@
+package gitrepo
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+const (
+	// TOKEN_ENV_PREFIX names per host token variables, GIT_TOKEN_<host> with
+	// dots written as underscores and hyphens as double underscores
+	TOKEN_ENV_PREFIX = "GIT_TOKEN_"
+	DEFAULT_SSH_USER = "git"
+)
+
+// defaultKeyFiles are the identities ssh itself tries, relative to ~/.ssh
+var defaultKeyFiles = []string{"id_ed25519", "id_ecdsa", "id_rsa"}
+
+// DefaultNetrcFile returns $NETRC or the netrc file in the home directory
+func DefaultNetrcFile() string {
+	if file := os.Getenv("NETRC"); file != "" {
+		return file
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	if runtime.GOOS == "windows" {
+		return filepath.Join(home, "_netrc")
+	}
+	return filepath.Join(home, ".netrc")
+}
+
+// authentication returns the credentials for a repository: a token or netrc
+// login for HTTPS, and agent or key file authentication with host key checking
+// for SSH. Credentials are never sent over plain HTTP. Callers close the
+// returned method when it is an io.Closer.
+func authentication(repoURL string) (transport.AuthMethod, error) {
+	u, err := url.Parse(repoURL)
+	if err != nil {
+		return nil, nil
+	}
+
+	switch u.Scheme {
+	case "https":
+		if auth := httpAuthentication(u.Hostname()); auth != nil {
+			return auth, nil
+		}
+	case "ssh":
+		return sshAuthentication(u)
+	}
+
+	return nil, nil
+}
+
+// httpAuthentication looks a host up in the configured tokens, GIT_TOKEN_<host>
+// and the netrc file. GITHUB_TOKEN and GITLAB_TOKEN are only sent to github.com
+// and gitlab.com; other hosts need a token of their own.
+func httpAuthentication(host string) *http.BasicAuth {
+	host = normalizeHost(host)
+
+	configMu.RLock()
+	token := config.Tokens[host]
+	login, hasLogin := netrc[host]
+	if !hasLogin {
+		login, hasLogin = netrc[""]
+	}
+	configMu.RUnlock()
+
+	if token != "" {
+		return hostTokenAuth(host, token)
+	}
+	if token := os.Getenv(tokenEnvName(host)); token != "" {
+		return hostTokenAuth(host, token)
+	}
+	if hasLogin && login.Password != "" {
+		return &http.BasicAuth{Username: login.Login, Password: login.Password}
+	}
+
+	switch host {
+	case "github.com":
+		if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+			return tokenAuth(token)
+		}
+	case "gitlab.com":
+		if token := os.Getenv("GITLAB_TOKEN"); token != "" {
+			return hostTokenAuth(host, token)
+		}
+	}
+
+	return nil
+}
+
+func hostTokenAuth(host, token string) *http.BasicAuth {
+	if strings.Contains(host, "gitlab") {
+		return &http.BasicAuth{Username: "gitlab-ci-token", Password: token}
+	}
+	return tokenAuth(token)
+}
+
+func tokenAuth(token string) *http.BasicAuth {
+	// Fine-grained GitHub tokens start with "github_pat_"
+	username := "token"
+	if strings.HasPrefix(token, "github_pat_") {
+		username = "x-access-token"
+	}
+	return &http.BasicAuth{Username: username, Password: token}
+}
+
+// sshKeys offers the keys of the SSH agent and the key files. Close releases
+// the agent connection, which signs on behalf of the agent keys until then.
+type sshKeys struct {
+	gitssh.PublicKeysCallback
+	agent net.Conn
+}
+
+func (k *sshKeys) Close() error {
+	if k.agent == nil {
+		return nil
+	}
+	return k.agent.Close()
+}
+
+func sshAuthentication(u *url.URL) (transport.AuthMethod, error) {
+	configMu.RLock()
+	knownHosts := config.KnownHostsFiles
+	signers := keySigners
+	explicitKeys := len(config.SSHKeyFiles) > 0
+	configMu.RUnlock()
+
+	if !explicitKeys {
+		signers = defaultKeySigners()
+	}
+
+	hostKeyCallback, err := gitssh.NewKnownHostsCallback(knownHosts...)
+	if err != nil {
+		return nil, fmt.Errorf("SSH host keys cannot be verified without a known_hosts file (add the host with ssh-keyscan): %w", err)
+	}
+
+	keys := &sshKeys{}
+	if socket := os.Getenv("SSH_AUTH_SOCK"); socket != "" {
+		// An agent that cannot be reached leaves the key files
+		if conn, err := net.Dial("unix", socket); err == nil {
+			if agentSigners, err := agent.NewClient(conn).Signers(); err == nil && len(agentSigners) > 0 {
+				keys.agent = conn
+				signers = append(agentSigners, signers...)
+			} else {
+				conn.Close()
+			}
+		}
+	}
+
+	if len(signers) == 0 {
+		return nil, fmt.Errorf("no SSH key to authenticate to %s: start ssh-agent with a key or pass --ssh-key-file", u.Hostname())
+	}
+
+	keys.User = u.User.Username()
+	if keys.User == "" {
+		keys.User = DEFAULT_SSH_USER
+	}
+	keys.Callback = func() ([]ssh.Signer, error) { return signers, nil }
+	keys.HostKeyCallback = hostKeyCallback
+
+	return keys, nil
+}
+
+// loadKeyFile reads an unencrypted private key. Keys with a passphrase are
+// used through ssh-agent.
+func loadKeyFile(file string) (ssh.Signer, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SSH key %s: %w", file, err)
+	}
+
+	signer, err := ssh.ParsePrivateKey(data)
+	var missing *ssh.PassphraseMissingError
+	if errors.As(err, &missing) {
+		return nil, fmt.Errorf("SSH key %s is encrypted; add it to ssh-agent instead", file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid SSH key %s: %w", file, err)
+	}
+
+	return signer, nil
+}
+
+// defaultKeySigners loads the default identities that exist and need no passphrase
+func defaultKeySigners() []ssh.Signer {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+
+	signers := []ssh.Signer{}
+	for _, name := range defaultKeyFiles {
+		if signer, err := loadKeyFile(filepath.Join(home, ".ssh", name)); err == nil {
+			signers = append(signers, signer)
+		}
+	}
+	return signers
+}
+
+type netrcLogin struct {
+	Login    string
+	Password string
+}
+
+// parseNetrc reads the machine and default entries of a netrc file, keyed by
+// host; the default entry is keyed by the empty string
+func parseNetrc(data string) map[string]netrcLogin {
+	logins := map[string]netrcLogin{}
+
+	machine, inEntry, inMacro := "", false, false
+	var login netrcLogin
+	flush := func() {
+		if inEntry {
+			if _, ok := logins[machine]; !ok {
+				logins[machine] = login
+			}
+		}
+		login = netrcLogin{}
+	}
+
+	for _, line := range strings.Split(data, "\n") {
+		// Macro definitions run until an empty line
+		if inMacro {
+			inMacro = strings.TrimSpace(line) != ""
+			continue
+		}
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		for i := 0; i < len(fields); i++ {
+			value := ""
+			if i+1 < len(fields) {
+				value = fields[i+1]
+			}
+
+			switch fields[i] {
+			case "machine":
+				flush()
+				machine, inEntry = normalizeHost(value), true
+				i++
+			case "default":
+				flush()
+				machine, inEntry = "", true
+			case "login":
+				login.Login = value
+				i++
+			case "password":
+				login.Password = value
+				i++
+			case "account":
+				i++
+			case "macdef":
+				inMacro = true
+				i = len(fields)
+			}
+		}
+	}
+	flush()
+
+	return logins
+}
+
+// tokenEnvName is the GIT_TOKEN_<host> variable of a host
+func tokenEnvName(host string) string {
+	encoded := strings.ReplaceAll(host, "-", "__")
+	encoded = strings.ReplaceAll(encoded, ".", "_")
+	return TOKEN_ENV_PREFIX + encoded
+}
+
+func normalizeHost(host string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(host), "/"))
+}
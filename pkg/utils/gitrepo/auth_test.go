@@ -0,0 +1,194 @@
+package gitrepo
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func TestParseNetrc(t *testing.T) {
+	data := `# private hosts
+machine GitLab.Example.com login deploy password s3cret
+machine github.com
+	login octocat
+	account ignored
+	password ghp_token
+macdef init
+machine macro.example.com login no password no
+
+default login anonymous password guest
+`
+	want := map[string]netrcLogin{
+		"gitlab.example.com": {Login: "deploy", Password: "s3cret"},
+		"github.com":         {Login: "octocat", Password: "ghp_token"},
+		"":                   {Login: "anonymous", Password: "guest"},
+	}
+
+	got := parseNetrc(data)
+	if len(got) != len(want) {
+		t.Errorf("parseNetrc() = %+v, want %+v", got, want)
+	}
+	for host, login := range want {
+		if got[host] != login {
+			t.Errorf("parseNetrc()[%q] = %+v, want %+v", host, got[host], login)
+		}
+	}
+}
+
+func TestHTTPAuthentication(t *testing.T) {
+	for _, name := range []string{"GITHUB_TOKEN", "GITLAB_TOKEN", "GIT_TOKEN"} {
+		t.Setenv(name, "")
+	}
+	t.Setenv("GIT_TOKEN_git_example__corp_com", "env-token")
+	t.Setenv("GITLAB_TOKEN", "gitlab-token")
+	t.Setenv("GITHUB_TOKEN", "github-token")
+	t.Setenv("GIT_TOKEN", "global-token")
+
+	netrcFile := filepath.Join(t.TempDir(), "netrc")
+	os.WriteFile(netrcFile, []byte("machine netrc.example.com login deploy password netrc-password\nmachine git.example-corp.com login deploy password unused\n"), 0o600)
+
+	err := Configure(Config{
+		Tokens:    map[string]string{"Flag.Example.com": "flag-token"},
+		NetrcFile: netrcFile,
+	})
+	if err != nil {
+		t.Fatalf("Configure() unexpected error: %v", err)
+	}
+	t.Cleanup(func() { Configure(Config{}) })
+
+	tests := []struct {
+		host     string
+		username string
+		password string
+	}{
+		{host: "flag.example.com", username: "token", password: "flag-token"},
+		{host: "git.example-corp.com", username: "token", password: "env-token"},
+		{host: "netrc.example.com", username: "deploy", password: "netrc-password"},
+		{host: "gitlab.com", username: "gitlab-ci-token", password: "gitlab-token"},
+		{host: "github.com", username: "token", password: "github-token"},
+		{host: "github.evil.example"},
+		{host: "gitlab.example.com"},
+		{host: "bitbucket.org"},
+	}
+
+	for _, tt := range tests {
+		auth := httpAuthentication(tt.host)
+		if tt.password == "" {
+			if auth != nil {
+				t.Errorf("httpAuthentication(%q) = %+v, want none", tt.host, auth)
+			}
+			continue
+		}
+		if auth == nil || auth.Username != tt.username || auth.Password != tt.password {
+			t.Errorf("httpAuthentication(%q) = %+v, want %s:%s", tt.host, auth, tt.username, tt.password)
+		}
+	}
+}
+
+func TestAuthenticationScheme(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "github-token")
+	if err := Configure(Config{Tokens: map[string]string{"git.example.com": "flag-token"}}); err != nil {
+		t.Fatalf("Configure() unexpected error: %v", err)
+	}
+	t.Cleanup(func() { Configure(Config{}) })
+
+	tests := []struct {
+		url      string
+		wantAuth bool
+	}{
+		{url: "https://github.com/org/repo.git", wantAuth: true},
+		{url: "https://git.example.com/org/repo.git", wantAuth: true},
+		{url: "http://github.com/org/repo.git"},
+		{url: "http://git.example.com/org/repo.git"},
+		{url: "https://github.evil.example/org/repo.git"},
+		{url: "file:///tmp/repo"},
+	}
+
+	for _, tt := range tests {
+		auth, err := authentication(tt.url)
+		if err != nil {
+			t.Fatalf("authentication(%q) unexpected error: %v", tt.url, err)
+		}
+		if (auth != nil) != tt.wantAuth {
+			t.Errorf("authentication(%q) = %+v, want auth %v", tt.url, auth, tt.wantAuth)
+		}
+	}
+}
+
+func TestSSHAuthentication(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("SSH_AUTH_SOCK", "")
+	t.Setenv("SSH_KNOWN_HOSTS", "")
+	t.Cleanup(func() { Configure(Config{}) })
+
+	dir := t.TempDir()
+	keyFile := writeKey(t, dir, "id_test", "")
+	encryptedKeyFile := writeKey(t, dir, "id_encrypted", "passphrase")
+	knownHosts := filepath.Join(dir, "known_hosts")
+	os.WriteFile(knownHosts, []byte("gitlab.example.com ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIOMqqnkVzrm0SdG6UOoqKLsabgH5C9okWi0dh2l9GKJl\n"), 0o600)
+
+	u, _ := url.Parse("ssh://deploy@gitlab.example.com/group/modules.git")
+
+	if err := Configure(Config{SSHKeyFiles: []string{keyFile}}); err != nil {
+		t.Fatalf("Configure() unexpected error: %v", err)
+	}
+	if _, err := sshAuthentication(u); err == nil || !strings.Contains(err.Error(), "known_hosts") {
+		t.Errorf("sshAuthentication() without known_hosts error = %v", err)
+	}
+
+	if err := Configure(Config{SSHKeyFiles: []string{keyFile}, KnownHostsFiles: []string{knownHosts}}); err != nil {
+		t.Fatalf("Configure() unexpected error: %v", err)
+	}
+	auth, err := sshAuthentication(u)
+	if err != nil {
+		t.Fatalf("sshAuthentication() unexpected error: %v", err)
+	}
+	keys := auth.(*sshKeys)
+	signers, _ := keys.Callback()
+	if keys.User != "deploy" || len(signers) != 1 || keys.HostKeyCallback == nil {
+		t.Errorf("sshAuthentication() = user %s, %d signers", keys.User, len(signers))
+	}
+
+	if err := Configure(Config{KnownHostsFiles: []string{knownHosts}}); err != nil {
+		t.Fatalf("Configure() unexpected error: %v", err)
+	}
+	if _, err := sshAuthentication(u); err == nil || !strings.Contains(err.Error(), "no SSH key") {
+		t.Errorf("sshAuthentication() without keys error = %v", err)
+	}
+
+	if err := Configure(Config{SSHKeyFiles: []string{encryptedKeyFile}}); err == nil || !strings.Contains(err.Error(), "ssh-agent") {
+		t.Errorf("Configure() with an encrypted key error = %v", err)
+	}
+}
+
+func writeKey(t *testing.T, dir, name, passphrase string) string {
+	t.Helper()
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() unexpected error: %v", err)
+	}
+
+	var block *pem.Block
+	if passphrase == "" {
+		block, err = ssh.MarshalPrivateKey(key, "")
+	} else {
+		block, err = ssh.MarshalPrivateKeyWithPassphrase(key, "", []byte(passphrase))
+	}
+	if err != nil {
+		t.Fatalf("MarshalPrivateKey() unexpected error: %v", err)
+	}
+
+	file := filepath.Join(dir, name)
+	if err := os.WriteFile(file, pem.EncodeToMemory(block), 0o600); err != nil {
+		t.Fatalf("WriteFile() unexpected error: %v", err)
+	}
+	return file
+}
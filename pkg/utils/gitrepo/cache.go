@@ -0,0 +1,298 @@
+package gitrepo
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+const (
+	DEFAULT_CACHE_MAX_BYTES = 2 << 30
+	DEFAULT_CACHE_MAX_AGE   = 30 * 24 * time.Hour
+)
+
+// mirrorRefSpecs keep every branch and tag of the remote, as git clone --mirror does
+var mirrorRefSpecs = []gitconfig.RefSpec{
+	"+refs/heads/*:refs/heads/*",
+	"+refs/tags/*:refs/tags/*",
+}
+
+var (
+	mirrorLocksMu sync.Mutex
+	mirrorLocks   = map[string]*sync.Mutex{}
+)
+
+// DefaultCacheDir returns the directory git mirrors are kept in by default
+func DefaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "terraform-mcp-server", "git")
+}
+
+// cloneCached checks a resolved ref out of the bare mirror of a repository,
+// creating the mirror on first use and fetching only when it lacks the commit
+func cloneCached(ctx context.Context, cfg Config, repoURL string, ref Ref) (billy.Filesystem, plumbing.Hash, error) {
+	dir := mirrorDir(cfg.CacheDir, repoURL)
+
+	lock := mirrorLock(dir)
+	lock.Lock()
+	defer lock.Unlock()
+
+	repo, err := openMirror(dir, repoURL)
+	if err != nil {
+		return nil, plumbing.ZeroHash, err
+	}
+
+	fetched := false
+	hash, err := mirrorCommit(repo, ref)
+	if err != nil {
+		if err := fetchMirror(ctx, repo, repoURL); err != nil {
+			return nil, plumbing.ZeroHash, fmt.Errorf("failed to fetch repository %s (ref: %s): %w", repoURL, refName(ref.Name), err)
+		}
+		fetched = true
+
+		if hash, err = mirrorCommit(repo, ref); err != nil {
+			return nil, plumbing.ZeroHash, fmt.Errorf("commit %s not found in %s", ref.Name, repoURL)
+		}
+	}
+
+	fs, err := checkoutCommit(repo, hash)
+	if err != nil {
+		return nil, plumbing.ZeroHash, fmt.Errorf("failed to checkout %s of %s: %w", hash, repoURL, err)
+	}
+
+	// The modification time of a mirror is when it was last used
+	now := time.Now()
+	_ = os.Chtimes(dir, now, now)
+	if fetched {
+		evictMirrors(cfg, dir)
+	}
+
+	return fs, hash, nil
+}
+
+func mirrorDir(cacheDir, repoURL string) string {
+	sum := sha256.Sum256([]byte(repoURL))
+	return filepath.Join(cacheDir, hex.EncodeToString(sum[:16]))
+}
+
+func mirrorLock(dir string) *sync.Mutex {
+	mirrorLocksMu.Lock()
+	defer mirrorLocksMu.Unlock()
+
+	lock, ok := mirrorLocks[dir]
+	if !ok {
+		lock = &sync.Mutex{}
+		mirrorLocks[dir] = lock
+	}
+	return lock
+}
+
+// openMirror opens the bare mirror in dir, creating it when it is missing.
+// A mirror that cannot be opened, e.g. after an interrupted first fetch, is recreated.
+func openMirror(dir, repoURL string) (*git.Repository, error) {
+	if repo, err := git.PlainOpen(dir); err == nil {
+		return repo, nil
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, fmt.Errorf("failed to reset git cache %s: %w", dir, err)
+	}
+	repo, err := git.PlainInit(dir, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create git cache %s: %w", dir, err)
+	}
+
+	_, err = repo.CreateRemote(&gitconfig.RemoteConfig{
+		Name:  git.DefaultRemoteName,
+		URLs:  []string{repoURL},
+		Fetch: mirrorRefSpecs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create git cache %s: %w", dir, err)
+	}
+
+	return repo, nil
+}
+
+func fetchMirror(ctx context.Context, repo *git.Repository, repoURL string) error {
+	auth, err := authentication(repoURL)
+	if err != nil {
+		return err
+	}
+	if closer, ok := auth.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	err = repo.FetchContext(ctx, &git.FetchOptions{
+		RemoteName: git.DefaultRemoteName,
+		RefSpecs:   mirrorRefSpecs,
+		Auth:       auth,
+		Prune:      true,
+	})
+	if errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return nil
+	}
+	return err
+}
+
+// mirrorCommit finds the commit of a ref in the mirror. Branches and tags
+// were resolved remotely; commits not at the tip of a ref are looked up by prefix.
+func mirrorCommit(repo *git.Repository, ref Ref) (plumbing.Hash, error) {
+	hash := plumbing.NewHash(ref.Commit)
+	if ref.Commit == "" {
+		resolved, err := repo.ResolveRevision(plumbing.Revision(ref.Name))
+		if err != nil {
+			return plumbing.ZeroHash, err
+		}
+		hash = *resolved
+	}
+
+	// Servers that do not advertise peeled tags give the annotated tag object
+	if tag, err := repo.TagObject(hash); err == nil {
+		commit, err := tag.Commit()
+		if err != nil {
+			return plumbing.ZeroHash, err
+		}
+		return commit.Hash, nil
+	}
+
+	if _, err := repo.CommitObject(hash); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	return hash, nil
+}
+
+// checkoutCommit writes the files of a commit into memory. Links and
+// submodules are left out, as they cannot be followed from memory.
+func checkoutCommit(repo *git.Repository, hash plumbing.Hash) (billy.Filesystem, error) {
+	commit, err := repo.CommitObject(hash)
+	if err != nil {
+		return nil, err
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	fs := memfs.New()
+	err = tree.Files().ForEach(func(f *object.File) error {
+		if f.Mode == filemode.Symlink {
+			return nil
+		}
+
+		r, err := f.Reader()
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+
+		w, err := fs.Create(f.Name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(w, r); err != nil {
+			w.Close()
+			return err
+		}
+		return w.Close()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return fs, nil
+}
+
+type mirrorUsage struct {
+	dir  string
+	used time.Time
+	size int64
+}
+
+// evictMirrors removes mirrors unused for longer than the maximum age, then
+// the least recently used ones until the cache fits its maximum size. keep is
+// never removed, and neither are mirrors in use.
+func evictMirrors(cfg Config, keep string) {
+	entries, err := os.ReadDir(cfg.CacheDir)
+	if err != nil {
+		return
+	}
+
+	mirrors := []mirrorUsage{}
+	total := int64(0)
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || !entry.IsDir() {
+			continue
+		}
+		dir := filepath.Join(cfg.CacheDir, entry.Name())
+
+		if dir != keep && cfg.CacheMaxAge > 0 && time.Since(info.ModTime()) > cfg.CacheMaxAge {
+			removeMirror(dir)
+			continue
+		}
+
+		usage := mirrorUsage{dir: dir, used: info.ModTime(), size: dirSize(dir)}
+		mirrors = append(mirrors, usage)
+		total += usage.size
+	}
+
+	if cfg.CacheMaxBytes <= 0 {
+		return
+	}
+
+	sort.Slice(mirrors, func(i, j int) bool { return mirrors[i].used.Before(mirrors[j].used) })
+	for _, m := range mirrors {
+		if total <= cfg.CacheMaxBytes {
+			break
+		}
+		if m.dir != keep && removeMirror(m.dir) {
+			total -= m.size
+		}
+	}
+}
+
+// removeMirror deletes a mirror unless another clone is using it
+func removeMirror(dir string) bool {
+	lock := mirrorLock(dir)
+	if !lock.TryLock() {
+		return false
+	}
+	defer lock.Unlock()
+
+	return os.RemoveAll(dir) == nil
+}
+
+func dirSize(dir string) int64 {
+	size := int64(0)
+	filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info, err := d.Info(); err == nil && !d.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
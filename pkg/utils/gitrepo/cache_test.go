@@ -0,0 +1,117 @@
+package gitrepo
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Yunsang-Jeong/terraform-config-parser/pkg/source"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestCloneCached(t *testing.T) {
+	repoURL, first, second := testRepository(t)
+	cacheDir := t.TempDir()
+
+	if err := Configure(Config{CacheDir: cacheDir}); err != nil {
+		t.Fatalf("Configure() unexpected error: %v", err)
+	}
+	t.Cleanup(func() { Configure(Config{}) })
+
+	clone := func(ref string) Checkout {
+		t.Helper()
+		checkout, err := Clone(context.Background(), repoURL, Options{SourceConfig: source.SourceConfig{Ref: ref}})
+		if err != nil {
+			t.Fatalf("Clone(%q) unexpected error: %v", ref, err)
+		}
+		return checkout
+	}
+
+	if checkout := clone("main"); checkout.Ref.Commit != second {
+		t.Errorf("Clone(main) commit = %s, want %s", checkout.Ref.Commit, second)
+	}
+
+	// A commit added upstream is only fetched when a ref needs it
+	third := commitUpstream(t, repoURL)
+
+	checkout := clone("v1.1.0")
+	data, _ := checkout.FS.ReadFile("main.tf")
+	if checkout.Ref.Commit != first || !strings.Contains(string(data), "first") {
+		t.Errorf("Clone(v1.1.0) = %s %q, want %s", checkout.Ref.Commit, data, first)
+	}
+	mirror, err := git.PlainOpen(mirrorDir(cacheDir, repoURL))
+	if err != nil {
+		t.Fatalf("mirror of %s not found: %v", repoURL, err)
+	}
+	if _, err := mirror.CommitObject(plumbing.NewHash(third)); err == nil {
+		t.Error("Clone(v1.1.0) fetched although the mirror had the tag")
+	}
+
+	if checkout := clone("main"); checkout.Ref.Commit != third {
+		t.Errorf("Clone(main) after an upstream commit = %s, want %s", checkout.Ref.Commit, third)
+	}
+	if checkout := clone(first[:8]); checkout.Ref.Commit != first {
+		t.Errorf("Clone(%s) commit = %s, want %s", first[:8], checkout.Ref.Commit, first)
+	}
+
+	entries, _ := os.ReadDir(cacheDir)
+	if len(entries) != 1 {
+		t.Errorf("cache has %d mirrors, want 1", len(entries))
+	}
+
+	if _, err := Clone(context.Background(), repoURL, Options{SourceConfig: source.SourceConfig{Ref: "deadbeefdeadbeef"}}); err == nil {
+		t.Error("Clone() expected error for a commit that does not exist")
+	}
+}
+
+func TestEvictMirrors(t *testing.T) {
+	cacheDir := t.TempDir()
+	mirror := func(name string, size int, age time.Duration) string {
+		dir := filepath.Join(cacheDir, name)
+		os.MkdirAll(dir, 0o755)
+		os.WriteFile(filepath.Join(dir, "pack"), make([]byte, size), 0o644)
+		used := time.Now().Add(-age)
+		os.Chtimes(dir, used, used)
+		return dir
+	}
+
+	expired := mirror("expired", 10, 48*time.Hour)
+	oldest := mirror("oldest", 100, 3*time.Hour)
+	older := mirror("older", 100, 2*time.Hour)
+	recent := mirror("recent", 100, time.Hour)
+	current := mirror("current", 100, 5*time.Hour)
+
+	evictMirrors(Config{CacheDir: cacheDir, CacheMaxBytes: 250, CacheMaxAge: 24 * time.Hour}, current)
+
+	for dir, want := range map[string]bool{expired: false, oldest: false, older: false, recent: true, current: true} {
+		if _, err := os.Stat(dir); (err == nil) != want {
+			t.Errorf("evictMirrors() kept %s = %v, want %v", filepath.Base(dir), err == nil, want)
+		}
+	}
+}
+
+// commitUpstream adds a commit to main of a repository made by testRepository
+func commitUpstream(t *testing.T, repoURL string) string {
+	t.Helper()
+
+	dir := strings.TrimPrefix(repoURL, "file://")
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		t.Fatalf("PlainOpen() unexpected error: %v", err)
+	}
+	worktree, _ := repo.Worktree()
+
+	os.WriteFile(filepath.Join(dir, "main.tf"), []byte("variable \"third\" {}\n"), 0o644)
+	worktree.Add("main.tf")
+	hash, err := worktree.Commit("third", &git.CommitOptions{Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Unix(1700000100, 0)}})
+	if err != nil {
+		t.Fatalf("Commit() unexpected error: %v", err)
+	}
+	return hash.String()
+}
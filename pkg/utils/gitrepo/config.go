@@ -0,0 +1,96 @@
+package gitrepo
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// Config holds the credentials used to clone private repositories and the
+// mirror cache clones are made from
+type Config struct {
+	// Tokens holds HTTPS tokens keyed by git host
+	Tokens map[string]string
+	// NetrcFile is read for HTTPS logins per host; a missing file is ignored
+	NetrcFile string
+	// SSHKeyFiles are private keys offered after the keys of the SSH agent.
+	// When empty, the unencrypted default identities in ~/.ssh are offered.
+	SSHKeyFiles []string
+	// KnownHostsFiles verify SSH host keys. When empty, $SSH_KNOWN_HOSTS or
+	// ~/.ssh/known_hosts and /etc/ssh/ssh_known_hosts are used.
+	KnownHostsFiles []string
+	// CacheDir keeps a bare mirror per repository when set, so repositories
+	// are fetched once and only again when a ref is not in the mirror
+	CacheDir string
+	// CacheMaxBytes and CacheMaxAge bound the mirrors kept; zero disables the bound
+	CacheMaxBytes int64
+	CacheMaxAge   time.Duration
+}
+
+var (
+	configMu   sync.RWMutex
+	config     Config
+	netrc      = map[string]netrcLogin{}
+	keySigners []ssh.Signer
+)
+
+// Configure replaces the git credentials and cache settings. Key files that
+// cannot be used are reported here rather than on the first clone.
+func Configure(cfg Config) error {
+	tokens := map[string]string{}
+	for host, token := range cfg.Tokens {
+		tokens[normalizeHost(host)] = token
+	}
+	cfg.Tokens = tokens
+
+	logins := map[string]netrcLogin{}
+	if cfg.NetrcFile != "" {
+		data, err := os.ReadFile(cfg.NetrcFile)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to read netrc file %s: %w", cfg.NetrcFile, err)
+		}
+		logins = parseNetrc(string(data))
+	}
+
+	signers := []ssh.Signer{}
+	for _, file := range cfg.SSHKeyFiles {
+		signer, err := loadKeyFile(file)
+		if err != nil {
+			return err
+		}
+		signers = append(signers, signer)
+	}
+
+	for _, file := range cfg.KnownHostsFiles {
+		if _, err := os.Stat(file); err != nil {
+			return fmt.Errorf("invalid known_hosts file: %w", err)
+		}
+	}
+
+	if cfg.CacheDir != "" {
+		if err := os.MkdirAll(cfg.CacheDir, 0o755); err != nil {
+			return fmt.Errorf("failed to create git cache directory %s: %w", cfg.CacheDir, err)
+		}
+	}
+
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	config = cfg
+	netrc = logins
+	keySigners = signers
+
+	return nil
+}
+
+// settings returns the current configuration
+func settings() Config {
+	configMu.RLock()
+	defer configMu.RUnlock()
+
+	return config
+}
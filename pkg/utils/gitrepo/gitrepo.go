@@ -0,0 +1,261 @@
+package gitrepo
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Yunsang-Jeong/terraform-mcp-server/pkg/utils"
+
+	"github.com/Yunsang-Jeong/terraform-config-parser/pkg/filesystem"
+	"github.com/Yunsang-Jeong/terraform-config-parser/pkg/source"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+var commitRegex = regexp.MustCompile(`^[a-f0-9]{7,64}$`)
+
+const (
+	REF_BRANCH = "branch"
+	REF_TAG    = "tag"
+	REF_COMMIT = "commit"
+)
+
+// Options selects the ref and subdirectory to clone and how much history to fetch
+type Options struct {
+	source.SourceConfig
+	// Depth is the number of commits fetched without the mirror cache; zero
+	// fetches only the one checked out
+	Depth int
+}
+
+// Ref is a branch, tag or commit resolved to the commit it points to
+type Ref struct {
+	// Name is the branch or tag name, or the commit as it was requested
+	Name string `json:"name"`
+	// Kind is one of the REF_* constants
+	Kind   string `json:"kind"`
+	Commit string `json:"commit"`
+}
+
+// Checkout is a repository cloned into memory
+type Checkout struct {
+	FS filesystem.FileReader
+	// Root is the path of the configured subdirectory
+	Root string
+	Ref  Ref
+}
+
+// Clone resolves the configured ref, fetches the repository into memory and
+// checks the ref out. With a cache directory configured, the ref is checked
+// out of the repository's mirror instead. The clone is aborted when ctx is done.
+func Clone(ctx context.Context, repoURL string, config Options) (Checkout, error) {
+	ref, err := ResolveRef(ctx, repoURL, config.Ref)
+	if err != nil {
+		return Checkout{}, err
+	}
+
+	rootPath := "."
+	if config.SubDir != "" {
+		rootPath = config.SubDir
+	}
+
+	if cfg := settings(); cfg.CacheDir != "" {
+		fs, hash, err := cloneCached(ctx, cfg, repoURL, ref)
+		if err != nil {
+			return Checkout{}, err
+		}
+		ref.Commit = hash.String()
+		return Checkout{FS: filesystem.NewBillyAdapter(fs), Root: rootPath, Ref: ref}, nil
+	}
+
+	fs := memfs.New()
+
+	cloneOptions := &git.CloneOptions{
+		URL:   repoURL,
+		Depth: max(config.Depth, 1),
+	}
+	auth, err := authentication(repoURL)
+	if err != nil {
+		return Checkout{}, err
+	}
+	if closer, ok := auth.(io.Closer); ok {
+		defer closer.Close()
+	}
+	cloneOptions.Auth = auth
+
+	switch ref.Kind {
+	case REF_BRANCH:
+		cloneOptions.ReferenceName = plumbing.NewBranchReferenceName(ref.Name)
+		cloneOptions.SingleBranch = true
+	case REF_TAG:
+		cloneOptions.ReferenceName = plumbing.NewTagReferenceName(ref.Name)
+		cloneOptions.SingleBranch = true
+	case REF_COMMIT:
+		// A commit cannot be cloned directly; fetch history and check it out afterwards
+		cloneOptions.Depth = 0
+		cloneOptions.NoCheckout = true
+	}
+
+	repo, err := git.CloneContext(ctx, memory.NewStorage(), fs, cloneOptions)
+	if err != nil {
+		return Checkout{}, fmt.Errorf("failed to clone repository %s (ref: %s): %w", repoURL, refName(config.Ref), err)
+	}
+
+	if ref.Kind == REF_COMMIT {
+		revision := ref.Commit
+		if revision == "" {
+			revision = ref.Name
+		}
+		hash, err := repo.ResolveRevision(plumbing.Revision(revision))
+		if err != nil {
+			return Checkout{}, fmt.Errorf("commit %s not found in %s", ref.Name, repoURL)
+		}
+
+		worktree, err := repo.Worktree()
+		if err != nil {
+			return Checkout{}, err
+		}
+		if err := worktree.Checkout(&git.CheckoutOptions{Hash: *hash}); err != nil {
+			return Checkout{}, fmt.Errorf("failed to checkout commit %s: %w", ref.Name, err)
+		}
+	}
+
+	// Report what was checked out, which is newer than the listing if the ref moved since
+	head, err := repo.Head()
+	if err != nil {
+		return Checkout{}, fmt.Errorf("failed to read the checked out commit: %w", err)
+	}
+	ref.Commit = head.Hash().String()
+
+	return Checkout{FS: filesystem.NewBillyAdapter(fs), Root: rootPath, Ref: ref}, nil
+}
+
+// ResolveRef looks a branch, tag or commit up in the refs the remote
+// advertises, without cloning. An empty ref is the default branch. Branches
+// win over tags of the same name, as with git clone --branch. A commit that
+// is not the tip of a ref is returned with an empty Commit; it is resolved
+// once the history is fetched.
+func ResolveRef(ctx context.Context, repoURL, ref string) (Ref, error) {
+	remote := git.NewRemote(memory.NewStorage(), &gitconfig.RemoteConfig{
+		Name: git.DefaultRemoteName,
+		URLs: []string{repoURL},
+	})
+
+	auth, err := authentication(repoURL)
+	if err != nil {
+		return Ref{}, err
+	}
+	if closer, ok := auth.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	listOptions := &git.ListOptions{Auth: auth}
+
+	refs, err := remote.ListContext(ctx, listOptions)
+	if err != nil {
+		return Ref{}, fmt.Errorf("failed to list refs of %s: %w", repoURL, err)
+	}
+
+	return resolveRef(repoURL, refs, ref)
+}
+
+func resolveRef(repoURL string, refs []*plumbing.Reference, ref string) (Ref, error) {
+	hashes := map[plumbing.ReferenceName]string{}
+	var head *plumbing.Reference
+	for _, r := range refs {
+		switch {
+		case r.Name() == plumbing.HEAD:
+			head = r
+		case r.Type() == plumbing.HashReference:
+			hashes[r.Name()] = r.Hash().String()
+		}
+	}
+	// commitOf peels annotated tags to the commit they tag
+	commitOf := func(name plumbing.ReferenceName) (string, bool) {
+		if peeled, ok := hashes[name+"^{}"]; ok {
+			return peeled, true
+		}
+		hash, ok := hashes[name]
+		return hash, ok
+	}
+
+	if ref == "" {
+		return defaultBranch(repoURL, head, hashes)
+	}
+
+	name := strings.TrimPrefix(strings.TrimPrefix(ref, "refs/heads/"), "refs/tags/")
+	if !strings.HasPrefix(ref, "refs/tags/") {
+		if commit, ok := commitOf(plumbing.NewBranchReferenceName(name)); ok {
+			return Ref{Name: name, Kind: REF_BRANCH, Commit: commit}, nil
+		}
+	}
+	if !strings.HasPrefix(ref, "refs/heads/") {
+		if commit, ok := commitOf(plumbing.NewTagReferenceName(name)); ok {
+			return Ref{Name: name, Kind: REF_TAG, Commit: commit}, nil
+		}
+	}
+
+	names := []string{}
+	for refName := range hashes {
+		if strings.HasSuffix(refName.String(), "^{}") || !(refName.IsBranch() || refName.IsTag()) {
+			continue
+		}
+		names = append(names, refName.Short())
+
+		if commit, _ := commitOf(refName); commitRegex.MatchString(ref) && strings.HasPrefix(commit, ref) {
+			return Ref{Name: ref, Kind: REF_COMMIT, Commit: commit}, nil
+		}
+	}
+
+	if commitRegex.MatchString(ref) {
+		return Ref{Name: ref, Kind: REF_COMMIT}, nil
+	}
+	if closest, ok := utils.Closest(name, names); ok {
+		return Ref{}, fmt.Errorf("ref %q not found in %s, did you mean %q?", ref, repoURL, closest)
+	}
+	return Ref{}, fmt.Errorf("ref %q not found in %s: it is not a branch, tag or commit SHA", ref, repoURL)
+}
+
+// defaultBranch resolves the remote HEAD. Servers that do not say which branch
+// HEAD is get the branch at the same commit, or the commit itself.
+func defaultBranch(repoURL string, head *plumbing.Reference, hashes map[plumbing.ReferenceName]string) (Ref, error) {
+	if head == nil {
+		return Ref{}, fmt.Errorf("%s has no default branch", repoURL)
+	}
+
+	if head.Type() == plumbing.SymbolicReference {
+		commit, ok := hashes[head.Target()]
+		if !ok {
+			return Ref{}, fmt.Errorf("default branch %s of %s has no commits", head.Target().Short(), repoURL)
+		}
+		return Ref{Name: head.Target().Short(), Kind: REF_BRANCH, Commit: commit}, nil
+	}
+
+	branches := []string{}
+	for refName, hash := range hashes {
+		if refName.IsBranch() && hash == head.Hash().String() {
+			branches = append(branches, refName.Short())
+		}
+	}
+	if len(branches) > 0 {
+		sort.Strings(branches)
+		return Ref{Name: branches[0], Kind: REF_BRANCH, Commit: head.Hash().String()}, nil
+	}
+
+	return Ref{Name: head.Hash().String(), Kind: REF_COMMIT, Commit: head.Hash().String()}, nil
+}
+
+func refName(ref string) string {
+	if ref == "" {
+		return "default"
+	}
+	return ref
+}
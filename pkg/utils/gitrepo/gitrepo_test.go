@@ -0,0 +1,106 @@
+package gitrepo
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Yunsang-Jeong/terraform-config-parser/pkg/source"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// testRepository creates a repository with two commits on main, a "feature"
+// branch and a lightweight and an annotated tag on the first commit
+func testRepository(t *testing.T) (string, string, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	repo, err := git.PlainInitWithOptions(dir, &git.PlainInitOptions{
+		InitOptions: git.InitOptions{DefaultBranch: plumbing.NewBranchReferenceName("main")},
+	})
+	if err != nil {
+		t.Fatalf("PlainInit() unexpected error: %v", err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("Worktree() unexpected error: %v", err)
+	}
+
+	signature := &object.Signature{Name: "test", Email: "test@example.com", When: time.Unix(1700000000, 0)}
+	commit := func(content string) string {
+		if err := os.WriteFile(filepath.Join(dir, "main.tf"), []byte(content), 0o644); err != nil {
+			t.Fatalf("WriteFile() unexpected error: %v", err)
+		}
+		if _, err := worktree.Add("main.tf"); err != nil {
+			t.Fatalf("Add() unexpected error: %v", err)
+		}
+		hash, err := worktree.Commit(content, &git.CommitOptions{Author: signature})
+		if err != nil {
+			t.Fatalf("Commit() unexpected error: %v", err)
+		}
+		return hash.String()
+	}
+
+	first := commit("variable \"first\" {}\n")
+	if _, err := repo.CreateTag("v1.0.0", plumbing.NewHash(first), nil); err != nil {
+		t.Fatalf("CreateTag() unexpected error: %v", err)
+	}
+	if _, err := repo.CreateTag("v1.1.0", plumbing.NewHash(first), &git.CreateTagOptions{Tagger: signature, Message: "v1.1.0"}); err != nil {
+		t.Fatalf("CreateTag() unexpected error: %v", err)
+	}
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.NewBranchReferenceName("feature"), plumbing.NewHash(first))); err != nil {
+		t.Fatalf("SetReference() unexpected error: %v", err)
+	}
+	second := commit("variable \"second\" {}\n")
+
+	return "file://" + dir, first, second
+}
+
+func TestClone(t *testing.T) {
+	repoURL, first, second := testRepository(t)
+
+	tests := []struct {
+		ref     string
+		want    Ref
+		content string
+		wantErr string
+	}{
+		{ref: "", want: Ref{Name: "main", Kind: REF_BRANCH, Commit: second}, content: "second"},
+		{ref: "feature", want: Ref{Name: "feature", Kind: REF_BRANCH, Commit: first}, content: "first"},
+		{ref: "refs/heads/main", want: Ref{Name: "main", Kind: REF_BRANCH, Commit: second}, content: "second"},
+		{ref: "v1.0.0", want: Ref{Name: "v1.0.0", Kind: REF_TAG, Commit: first}, content: "first"},
+		{ref: "v1.1.0", want: Ref{Name: "v1.1.0", Kind: REF_TAG, Commit: first}, content: "first"},
+		{ref: first, want: Ref{Name: first, Kind: REF_COMMIT, Commit: first}, content: "first"},
+		{ref: first[:7], want: Ref{Name: first[:7], Kind: REF_COMMIT, Commit: first}, content: "first"},
+		{ref: "v1.0.1", wantErr: `did you mean "v1.0.0"`},
+		{ref: "does-not-exist", wantErr: "not found"},
+		{ref: "deadbeefdeadbeef", wantErr: "commit deadbeefdeadbeef not found"},
+	}
+
+	for _, tt := range tests {
+		checkout, err := Clone(context.Background(), repoURL, Options{SourceConfig: source.SourceConfig{Ref: tt.ref}})
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Clone(%q) error = %v, want %q", tt.ref, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Clone(%q) unexpected error: %v", tt.ref, err)
+			continue
+		}
+		if checkout.Ref != tt.want {
+			t.Errorf("Clone(%q) ref = %+v, want %+v", tt.ref, checkout.Ref, tt.want)
+		}
+		data, err := checkout.FS.ReadFile("main.tf")
+		if err != nil || !strings.Contains(string(data), tt.content) {
+			t.Errorf("Clone(%q) main.tf = %q, %v, want %s", tt.ref, data, err, tt.content)
+		}
+	}
+}
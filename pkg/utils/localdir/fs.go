@@ -0,0 +1,83 @@
+package localdir
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// FS reads files under a root directory for the configuration parser. Every
+// path is checked after resolving symlinks, so links cannot lead out of the root.
+type FS struct {
+	root string
+}
+
+// NewFS returns a reader confined to root, which must be free of symlinks as
+// the roots of a Dir are
+func NewFS(root string) *FS {
+	return &FS{root: root}
+}
+
+func (f *FS) DirExists(dirname string) (bool, error) {
+	path, err := f.resolve(dirname)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return false, err
+	}
+	return info.IsDir(), nil
+}
+
+func (f *FS) ReadDir(dirname string) ([]os.FileInfo, error) {
+	path, err := f.resolve(dirname)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := []os.FileInfo{}
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+func (f *FS) ReadFile(filename string) ([]byte, error) {
+	path, err := f.resolve(filename)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(path)
+}
+
+// resolve turns a path relative to the root, or absolute, into a path inside the root
+func (f *FS) resolve(name string) (string, error) {
+	if !filepath.IsAbs(name) {
+		name = filepath.Join(f.root, name)
+	}
+
+	resolved, err := filepath.EvalSymlinks(name)
+	if err != nil {
+		return "", err
+	}
+	if !within(f.root, resolved) {
+		return "", fmt.Errorf("%s is outside of %s", name, f.root)
+	}
+
+	return resolved, nil
+}
@@ -0,0 +1,151 @@
+package localdir
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+var (
+	rootsMu sync.RWMutex
+	// roots are absolute and free of symlinks, so containment is a path prefix check
+	roots []string
+)
+
+// Dir is a directory inside one of the allowed roots
+type Dir struct {
+	// Root is the allowed root the directory is in
+	Root string
+	// Path is the absolute path of the directory with symlinks resolved
+	Path string
+	// Rel is Path relative to Root
+	Rel string
+}
+
+// Configure replaces the directories local modules and schema files may be
+// read from. An empty list disables reading local files.
+func Configure(dirs []string) error {
+	resolved := []string{}
+	for _, dir := range dirs {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return fmt.Errorf("invalid local root %s: %w", dir, err)
+		}
+		if abs, err = filepath.EvalSymlinks(abs); err != nil {
+			return fmt.Errorf("invalid local root %s: %w", dir, err)
+		}
+		info, err := os.Stat(abs)
+		if err != nil {
+			return fmt.Errorf("invalid local root %s: %w", dir, err)
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("local root %s is not a directory", dir)
+		}
+		resolved = append(resolved, abs)
+	}
+
+	rootsMu.Lock()
+	defer rootsMu.Unlock()
+
+	roots = resolved
+	return nil
+}
+
+// Roots returns the directories local modules may be read from
+func Roots() []string {
+	rootsMu.RLock()
+	defer rootsMu.RUnlock()
+
+	return append([]string{}, roots...)
+}
+
+// Resolve finds a directory inside the allowed roots. Absolute paths must be
+// inside a root once symlinks are resolved; relative paths are tried against
+// each root in turn.
+func Resolve(path string) (Dir, error) {
+	return resolve(path, true)
+}
+
+// ResolveFile finds a regular file inside the allowed roots the way Resolve
+// finds directories, returning its absolute path with symlinks resolved
+func ResolveFile(path string) (string, error) {
+	file, err := resolve(path, false)
+	if err != nil {
+		return "", err
+	}
+	return file.Path, nil
+}
+
+func resolve(path string, wantDir bool) (Dir, error) {
+	allowed := Roots()
+	if len(allowed) == 0 {
+		return Dir{}, fmt.Errorf("reading local files is disabled: start the server with --local-root")
+	}
+
+	candidates := []string{}
+	if filepath.IsAbs(path) {
+		candidates = append(candidates, path)
+	} else {
+		for _, root := range allowed {
+			candidates = append(candidates, filepath.Join(root, path))
+		}
+	}
+
+	var lastErr error
+	for _, candidate := range candidates {
+		resolved, err := filepath.EvalSymlinks(candidate)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		root, ok := rootOf(allowed, resolved)
+		if !ok {
+			lastErr = fmt.Errorf("%s is outside of the allowed roots (%s)", path, strings.Join(allowed, ", "))
+			continue
+		}
+
+		info, err := os.Stat(resolved)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if wantDir && !info.IsDir() {
+			lastErr = fmt.Errorf("%s is not a directory", path)
+			continue
+		}
+		if !wantDir && !info.Mode().IsRegular() {
+			lastErr = fmt.Errorf("%s is not a file", path)
+			continue
+		}
+
+		rel, _ := filepath.Rel(root, resolved)
+		return Dir{Root: root, Path: resolved, Rel: filepath.ToSlash(rel)}, nil
+	}
+
+	if errors.Is(lastErr, os.ErrNotExist) {
+		return Dir{}, fmt.Errorf("%s not found in the allowed roots (%s)", path, strings.Join(allowed, ", "))
+	}
+	return Dir{}, lastErr
+}
+
+// rootOf returns the allowed root a resolved path is in
+func rootOf(allowed []string, path string) (string, bool) {
+	for _, root := range allowed {
+		if within(root, path) {
+			return root, true
+		}
+	}
+	return "", false
+}
+
+func within(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel)
+}
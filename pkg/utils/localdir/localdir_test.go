@@ -0,0 +1,136 @@
+package localdir
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testRoots creates an allowed root with a module, and a directory outside
+// of it that symlinks inside the root point to
+func testRoots(t *testing.T) (string, string) {
+	t.Helper()
+
+	base, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatalf("EvalSymlinks() unexpected error: %v", err)
+	}
+	root := filepath.Join(base, "repo")
+	outside := filepath.Join(base, "secrets")
+
+	os.MkdirAll(filepath.Join(root, "modules", "vpc"), 0o755)
+	os.MkdirAll(outside, 0o755)
+	os.WriteFile(filepath.Join(root, "modules", "vpc", "main.tf"), []byte("variable \"cidr\" {}\n"), 0o644)
+	os.WriteFile(filepath.Join(outside, "main.tf"), []byte("variable \"secret\" {}\n"), 0o644)
+
+	os.Symlink(outside, filepath.Join(root, "escape"))
+	os.Symlink(filepath.Join(root, "modules", "vpc"), filepath.Join(root, "vpc"))
+
+	if err := Configure([]string{root}); err != nil {
+		t.Fatalf("Configure() unexpected error: %v", err)
+	}
+	t.Cleanup(func() { Configure(nil) })
+
+	return root, outside
+}
+
+func TestResolve(t *testing.T) {
+	root, outside := testRoots(t)
+
+	tests := []struct {
+		path    string
+		wantRel string
+		wantErr string
+	}{
+		{path: "modules/vpc", wantRel: "modules/vpc"},
+		{path: filepath.Join(root, "modules", "vpc"), wantRel: "modules/vpc"},
+		{path: ".", wantRel: "."},
+		{path: "vpc", wantRel: "modules/vpc"},
+		{path: "modules/../modules/vpc", wantRel: "modules/vpc"},
+		{path: "../secrets", wantErr: "outside of the allowed roots"},
+		{path: outside, wantErr: "outside of the allowed roots"},
+		{path: "escape", wantErr: "outside of the allowed roots"},
+		{path: "modules/vpc/main.tf", wantErr: "not a directory"},
+		{path: "modules/missing", wantErr: "not found"},
+	}
+
+	for _, tt := range tests {
+		dir, err := Resolve(tt.path)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Resolve(%q) = %+v, %v, want error %q", tt.path, dir, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || dir.Rel != tt.wantRel || dir.Root != root {
+			t.Errorf("Resolve(%q) = %+v, %v, want %s in %s", tt.path, dir, err, tt.wantRel, root)
+		}
+	}
+
+	Configure(nil)
+	if _, err := Resolve("modules/vpc"); err == nil || !strings.Contains(err.Error(), "--local-root") {
+		t.Errorf("Resolve() without roots error = %v", err)
+	}
+}
+
+func TestResolveFile(t *testing.T) {
+	root, outside := testRoots(t)
+
+	tests := []struct {
+		path    string
+		want    string
+		wantErr string
+	}{
+		{path: "modules/vpc/main.tf", want: filepath.Join(root, "modules", "vpc", "main.tf")},
+		{path: "vpc/main.tf", want: filepath.Join(root, "modules", "vpc", "main.tf")},
+		{path: filepath.Join(outside, "main.tf"), wantErr: "outside of the allowed roots"},
+		{path: "escape/main.tf", wantErr: "outside of the allowed roots"},
+		{path: "modules/vpc", wantErr: "not a file"},
+	}
+
+	for _, tt := range tests {
+		file, err := ResolveFile(tt.path)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ResolveFile(%q) = %s, %v, want error %q", tt.path, file, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || file != tt.want {
+			t.Errorf("ResolveFile(%q) = %s, %v, want %s", tt.path, file, err, tt.want)
+		}
+	}
+}
+
+func TestFS(t *testing.T) {
+	root, _ := testRoots(t)
+	os.Symlink(filepath.Join(root, "escape", "main.tf"), filepath.Join(root, "modules", "vpc", "linked.tf"))
+
+	fs := NewFS(root)
+
+	if data, err := fs.ReadFile(filepath.Join(root, "modules", "vpc", "main.tf")); err != nil || !strings.Contains(string(data), "cidr") {
+		t.Errorf("ReadFile(main.tf) = %q, %v", data, err)
+	}
+	if _, err := fs.ReadFile(filepath.Join(root, "modules", "vpc", "linked.tf")); err == nil {
+		t.Error("ReadFile() followed a link out of the root")
+	}
+	if _, err := fs.ReadFile(filepath.Join(root, "..", "secrets", "main.tf")); err == nil {
+		t.Error("ReadFile() read a file outside of the root")
+	}
+
+	if exists, err := fs.DirExists("modules/vpc"); err != nil || !exists {
+		t.Errorf("DirExists(modules/vpc) = %v, %v, want true", exists, err)
+	}
+	if exists, _ := fs.DirExists("modules/missing"); exists {
+		t.Error("DirExists(modules/missing) = true, want false")
+	}
+	if _, err := fs.DirExists("escape"); err == nil {
+		t.Error("DirExists() followed a link out of the root")
+	}
+
+	infos, err := fs.ReadDir("modules/vpc")
+	if err != nil || len(infos) != 2 {
+		t.Errorf("ReadDir(modules/vpc) = %d entries, %v, want 2", len(infos), err)
+	}
+}
@@ -0,0 +1,310 @@
+package modconfig
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Yunsang-Jeong/terraform-mcp-server/pkg/utils"
+
+	"github.com/Yunsang-Jeong/terraform-config-parser/pkg/filesystem"
+	"github.com/Yunsang-Jeong/terraform-config-parser/pkg/parser"
+	"github.com/Yunsang-Jeong/terraform-config-parser/pkg/parser/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const (
+	SECTION_VARIABLES    = "variables"
+	SECTION_OUTPUTS      = "outputs"
+	SECTION_PROVIDERS    = "providers"
+	SECTION_RESOURCES    = "resources"
+	SECTION_DATA_SOURCES = "data_sources"
+	SECTION_MODULE_CALLS = "module_calls"
+	SECTION_LOCALS       = "locals"
+)
+
+// SECTIONS are the parts of a module configuration that can be selected
+var SECTIONS = []string{
+	SECTION_VARIABLES,
+	SECTION_OUTPUTS,
+	SECTION_PROVIDERS,
+	SECTION_RESOURCES,
+	SECTION_DATA_SOURCES,
+	SECTION_MODULE_CALLS,
+	SECTION_LOCALS,
+}
+
+// DETAIL_SECTIONS are only read by a detailed parse
+var DETAIL_SECTIONS = []string{SECTION_RESOURCES, SECTION_DATA_SOURCES, SECTION_MODULE_CALLS, SECTION_LOCALS}
+
+// Config is a parsed module configuration. The parser's summary keeps its
+// shape (variables, outputs, terraform); a detailed parse adds the blocks
+// that make up the module's implementation.
+type Config struct {
+	Variables   []*schema.Variable  `json:"variables,omitempty"`
+	Outputs     []*schema.Output    `json:"outputs,omitempty"`
+	Terraform   []*schema.Terraform `json:"terraform,omitempty"`
+	Providers   []Provider          `json:"providers,omitempty"`
+	Resources   []Resource          `json:"resources,omitempty"`
+	DataSources []Resource          `json:"data_sources,omitempty"`
+	ModuleCalls []ModuleCall        `json:"module_calls,omitempty"`
+	Locals      []Local             `json:"locals,omitempty"`
+}
+
+// Resource is a resource or data block
+type Resource struct {
+	Type     string `json:"type"`
+	Name     string `json:"name"`
+	Provider string `json:"provider,omitempty"`
+	Count    string `json:"count,omitempty"`
+	ForEach  string `json:"for_each,omitempty"`
+	// Pos is the file and line the block starts at
+	Pos string `json:"pos"`
+}
+
+// ModuleCall is a module block
+type ModuleCall struct {
+	Name    string `json:"name"`
+	Source  string `json:"source"`
+	Version string `json:"version,omitempty"`
+	Count   string `json:"count,omitempty"`
+	ForEach string `json:"for_each,omitempty"`
+	Pos     string `json:"pos"`
+}
+
+// Provider is a provider configuration block
+type Provider struct {
+	Name  string `json:"name"`
+	Alias string `json:"alias,omitempty"`
+	Pos   string `json:"pos"`
+}
+
+// Local is a named value of a locals block. As in the parser's summary,
+// scalars are returned as values and other expressions as their source text.
+type Local struct {
+	Name  string      `json:"name"`
+	Value interface{} `json:"value"`
+	Pos   string      `json:"pos"`
+}
+
+// CheckSections validates section names and reports whether any of them
+// needs a detailed parse
+func CheckSections(sections []string) (bool, error) {
+	detailed := false
+	for _, section := range sections {
+		if !utils.IsInList(section, SECTIONS) {
+			if closest, ok := utils.Closest(section, SECTIONS); ok {
+				return false, fmt.Errorf("unknown section %q, did you mean %q?", section, closest)
+			}
+			return false, fmt.Errorf("unknown section %q: expected one of %s", section, strings.Join(SECTIONS, ", "))
+		}
+		if utils.IsInList(section, DETAIL_SECTIONS) {
+			detailed = true
+		}
+	}
+	return detailed, nil
+}
+
+// Parse reads the module in dir. The parser provides variables, outputs and
+// the terraform block; a detailed parse also reads resources, data sources,
+// module calls, provider configurations and locals.
+func Parse(fs filesystem.FileReader, dir string, detailed bool) (*Config, error) {
+	mode := parser.Simple
+	if detailed {
+		mode = parser.Detail
+	}
+
+	tfConfig, err := parser.NewParser(fs, mode).ParseTerraformWorkspace(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &Config{
+		Variables: tfConfig.Variables,
+		Outputs:   tfConfig.Outputs,
+		Terraform: tfConfig.Terraform,
+	}
+	if !detailed {
+		return config, nil
+	}
+
+	if err := parseDetail(fs, dir, config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// Select keeps the given sections of the configuration; all of them when
+// sections is empty. The providers section covers the terraform block too.
+func (c *Config) Select(sections []string) *Config {
+	if len(sections) == 0 {
+		return c
+	}
+
+	selected := &Config{}
+	for _, section := range sections {
+		switch section {
+		case SECTION_VARIABLES:
+			selected.Variables = c.Variables
+		case SECTION_OUTPUTS:
+			selected.Outputs = c.Outputs
+		case SECTION_PROVIDERS:
+			selected.Terraform = c.Terraform
+			selected.Providers = c.Providers
+		case SECTION_RESOURCES:
+			selected.Resources = c.Resources
+		case SECTION_DATA_SOURCES:
+			selected.DataSources = c.DataSources
+		case SECTION_MODULE_CALLS:
+			selected.ModuleCalls = c.ModuleCalls
+		case SECTION_LOCALS:
+			selected.Locals = c.Locals
+		}
+	}
+	return selected
+}
+
+// parseDetail reads the blocks the parser leaves out of the .tf files of dir
+func parseDetail(fs filesystem.FileReader, dir string, config *Config) error {
+	files, err := fs.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read terraform workspace directory %s: %w", dir, err)
+	}
+
+	names := []string{}
+	for _, file := range files {
+		if !file.IsDir() && filepath.Ext(file.Name()) == ".tf" {
+			names = append(names, file.Name())
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		content, err := fs.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return fmt.Errorf("failed to read terraform file %s: %w", name, err)
+		}
+
+		file, diags := hclsyntax.ParseConfig(content, name, hcl.InitialPos)
+		if diags.HasErrors() {
+			return fmt.Errorf("failed to parse HCL syntax in %s: %w", name, diags)
+		}
+
+		for _, block := range file.Body.(*hclsyntax.Body).Blocks {
+			readBlock(config, block, content)
+		}
+	}
+
+	return nil
+}
+
+func readBlock(config *Config, block *hclsyntax.Block, src []byte) {
+	pos := fmt.Sprintf("%s:%d", block.DefRange().Filename, block.DefRange().Start.Line)
+	attrs := block.Body.Attributes
+
+	switch block.Type {
+	case "resource", "data":
+		if len(block.Labels) != 2 {
+			return
+		}
+		resource := Resource{
+			Type:     block.Labels[0],
+			Name:     block.Labels[1],
+			Provider: expressionText(attrs["provider"], src),
+			Count:    expressionText(attrs["count"], src),
+			ForEach:  expressionText(attrs["for_each"], src),
+			Pos:      pos,
+		}
+		if block.Type == "resource" {
+			config.Resources = append(config.Resources, resource)
+		} else {
+			config.DataSources = append(config.DataSources, resource)
+		}
+
+	case "module":
+		if len(block.Labels) != 1 {
+			return
+		}
+		config.ModuleCalls = append(config.ModuleCalls, ModuleCall{
+			Name:    block.Labels[0],
+			Source:  expressionText(attrs["source"], src),
+			Version: expressionText(attrs["version"], src),
+			Count:   expressionText(attrs["count"], src),
+			ForEach: expressionText(attrs["for_each"], src),
+			Pos:     pos,
+		})
+
+	case "provider":
+		if len(block.Labels) != 1 {
+			return
+		}
+		config.Providers = append(config.Providers, Provider{
+			Name:  block.Labels[0],
+			Alias: expressionText(attrs["alias"], src),
+			Pos:   pos,
+		})
+
+	case "locals":
+		names := []string{}
+		for name := range attrs {
+			names = append(names, name)
+		}
+		sort.Slice(names, func(i, j int) bool { return attrs[names[i]].SrcRange.Start.Byte < attrs[names[j]].SrcRange.Start.Byte })
+
+		for _, name := range names {
+			attr := attrs[name]
+			config.Locals = append(config.Locals, Local{
+				Name:  name,
+				Value: expressionValue(attr, src),
+				Pos:   fmt.Sprintf("%s:%d", attr.SrcRange.Filename, attr.SrcRange.Start.Line),
+			})
+		}
+	}
+}
+
+// expressionText returns a string literal as its value and any other expression as its source text
+func expressionText(attr *hclsyntax.Attribute, src []byte) string {
+	if attr == nil {
+		return ""
+	}
+	if value, ok := expressionValue(attr, src).(string); ok {
+		return value
+	}
+	return strings.TrimSpace(string(attr.Expr.Range().SliceBytes(src)))
+}
+
+// expressionValue decodes constant scalars and returns other expressions as their source text
+func expressionValue(attr *hclsyntax.Attribute, src []byte) interface{} {
+	raw := strings.TrimSpace(string(attr.Expr.Range().SliceBytes(src)))
+	if len(attr.Expr.Variables()) > 0 {
+		return raw
+	}
+	if _, isCall := attr.Expr.(*hclsyntax.FunctionCallExpr); isCall {
+		return raw
+	}
+
+	value, diags := attr.Expr.Value(nil)
+	if diags.HasErrors() || !value.IsWhollyKnown() {
+		return raw
+	}
+	if value.IsNull() {
+		return nil
+	}
+
+	switch value.Type() {
+	case cty.String:
+		return value.AsString()
+	case cty.Bool:
+		return value.True()
+	case cty.Number:
+		if i, accuracy := value.AsBigFloat().Int64(); accuracy == 0 {
+			return i
+		}
+		f, _ := value.AsBigFloat().Float64()
+		return f
+	}
+	return raw
+}
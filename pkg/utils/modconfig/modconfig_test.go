@@ -0,0 +1,141 @@
+package modconfig
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Yunsang-Jeong/terraform-config-parser/pkg/filesystem"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-billy/v5/util"
+)
+
+const testMain = `variable "name" {
+  type = string
+}
+
+provider "aws" {
+  alias  = "east"
+  region = "us-east-1"
+}
+
+resource "aws_vpc" "this" {
+  count    = var.create ? 1 : 0
+  provider = aws.east
+}
+
+data "aws_region" "current" {}
+
+module "subnets" {
+  source   = "terraform-aws-modules/vpc/aws//modules/subnets"
+  version  = "~> 5.0"
+  for_each = toset(var.zones)
+}
+
+locals {
+  prefix = "app"
+  size   = 3
+  tags   = merge(var.tags, { Name = var.name })
+}
+
+output "id" {
+  value = aws_vpc.this[0].id
+}
+`
+
+func testFS(t *testing.T) filesystem.FileReader {
+	t.Helper()
+
+	fs := memfs.New()
+	util.WriteFile(fs, "module/main.tf", []byte(testMain), 0o644)
+	util.WriteFile(fs, "module/README.md", []byte("# module\n"), 0o644)
+	return filesystem.NewBillyAdapter(fs)
+}
+
+func TestParse(t *testing.T) {
+	simple, err := Parse(testFS(t), "module", false)
+	if err != nil {
+		t.Fatalf("Parse() unexpected error: %v", err)
+	}
+	if len(simple.Variables) != 1 || len(simple.Outputs) != 1 {
+		t.Errorf("Parse() variables = %d, outputs = %d, want 1 and 1", len(simple.Variables), len(simple.Outputs))
+	}
+	if simple.Resources != nil || simple.ModuleCalls != nil || simple.Locals != nil {
+		t.Errorf("Parse() without detail = %+v, want no detailed sections", simple)
+	}
+
+	config, err := Parse(testFS(t), "module", true)
+	if err != nil {
+		t.Fatalf("Parse() unexpected error: %v", err)
+	}
+
+	wantResources := []Resource{{Type: "aws_vpc", Name: "this", Provider: "aws.east", Count: "var.create ? 1 : 0", Pos: "main.tf:10"}}
+	if !reflect.DeepEqual(config.Resources, wantResources) {
+		t.Errorf("Parse() resources = %+v, want %+v", config.Resources, wantResources)
+	}
+	wantDataSources := []Resource{{Type: "aws_region", Name: "current", Pos: "main.tf:15"}}
+	if !reflect.DeepEqual(config.DataSources, wantDataSources) {
+		t.Errorf("Parse() data sources = %+v, want %+v", config.DataSources, wantDataSources)
+	}
+	wantModuleCalls := []ModuleCall{{Name: "subnets", Source: "terraform-aws-modules/vpc/aws//modules/subnets", Version: "~> 5.0", ForEach: "toset(var.zones)", Pos: "main.tf:17"}}
+	if !reflect.DeepEqual(config.ModuleCalls, wantModuleCalls) {
+		t.Errorf("Parse() module calls = %+v, want %+v", config.ModuleCalls, wantModuleCalls)
+	}
+	wantProviders := []Provider{{Name: "aws", Alias: "east", Pos: "main.tf:5"}}
+	if !reflect.DeepEqual(config.Providers, wantProviders) {
+		t.Errorf("Parse() providers = %+v, want %+v", config.Providers, wantProviders)
+	}
+	wantLocals := []Local{
+		{Name: "prefix", Value: "app", Pos: "main.tf:24"},
+		{Name: "size", Value: int64(3), Pos: "main.tf:25"},
+		{Name: "tags", Value: "merge(var.tags, { Name = var.name })", Pos: "main.tf:26"},
+	}
+	if !reflect.DeepEqual(config.Locals, wantLocals) {
+		t.Errorf("Parse() locals = %+v, want %+v", config.Locals, wantLocals)
+	}
+}
+
+func TestCheckSections(t *testing.T) {
+	tests := []struct {
+		sections     []string
+		wantDetailed bool
+		wantErr      string
+	}{
+		{sections: nil},
+		{sections: []string{"variables", "outputs", "providers"}},
+		{sections: []string{"variables", "resources"}, wantDetailed: true},
+		{sections: []string{"locals"}, wantDetailed: true},
+		{sections: []string{"variable"}, wantErr: `did you mean "variables"`},
+	}
+
+	for _, tt := range tests {
+		detailed, err := CheckSections(tt.sections)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("CheckSections(%v) error = %v, want %s", tt.sections, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || detailed != tt.wantDetailed {
+			t.Errorf("CheckSections(%v) = %v, %v, want %v", tt.sections, detailed, err, tt.wantDetailed)
+		}
+	}
+}
+
+func TestSelect(t *testing.T) {
+	config, err := Parse(testFS(t), "module", true)
+	if err != nil {
+		t.Fatalf("Parse() unexpected error: %v", err)
+	}
+
+	if got := config.Select(nil); got != config {
+		t.Errorf("Select(nil) = %+v, want the whole configuration", got)
+	}
+
+	got := config.Select([]string{"variables", "module_calls"})
+	want := &Config{Variables: config.Variables, ModuleCalls: config.ModuleCalls}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Select() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,316 @@
+package modsource
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Yunsang-Jeong/terraform-config-parser/pkg/filesystem"
+
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-billy/v5/util"
+)
+
+const (
+	// MAX_ARCHIVE_BYTES bounds both the download and the extracted size of an archive
+	MAX_ARCHIVE_BYTES = 256 << 20
+	DOWNLOAD_TIMEOUT  = 5 * time.Minute
+)
+
+var httpClient = &http.Client{Timeout: DOWNLOAD_TIMEOUT}
+
+// terraformGetMeta is the HTML form of the X-Terraform-Get header
+var terraformGetMeta = regexp.MustCompile(`(?i)<meta\s+name=["']terraform-get["']\s+content=["']([^"']+)["']`)
+
+// fetchHTTP downloads and extracts an archive. An HTTP URL that is not an
+// archive is asked where the module is, through the X-Terraform-Get header or
+// a terraform-get meta tag, as go-getter does.
+func fetchHTTP(ctx context.Context, src Source, indirections int) (Module, error) {
+	rawURL := src.URL
+	if src.Getter == GETTER_GCS {
+		var err error
+		if rawURL, err = gcsObjectURL(rawURL); err != nil {
+			return Module{}, err
+		}
+	}
+
+	if src.Archive == "" {
+		if src.Getter != GETTER_HTTP {
+			return Module{}, fmt.Errorf("%s sources must be archives (%s): %s", src.Getter, strings.Join(ARCHIVE_TYPES, ", "), src)
+		}
+		return followTerraformGet(ctx, src, indirections)
+	}
+
+	data, err := download(ctx, rawURL, nil)
+	if err != nil {
+		return Module{}, err
+	}
+
+	fs := memfs.New()
+	if err := extract(fs, data, src.Archive); err != nil {
+		return Module{}, fmt.Errorf("failed to extract %s: %w", src.URL, err)
+	}
+
+	root := "."
+	if src.Subdir != "" {
+		root = src.Subdir
+		if info, err := fs.Stat(root); err != nil || !info.IsDir() {
+			return Module{}, fmt.Errorf("subdirectory %s not found in %s", src.Subdir, src.URL)
+		}
+	}
+
+	return Module{FS: filesystem.NewBillyAdapter(fs), Root: root, Source: src}, nil
+}
+
+func followTerraformGet(ctx context.Context, src Source, indirections int) (Module, error) {
+	u, err := url.Parse(src.URL)
+	if err != nil {
+		return Module{}, err
+	}
+	q := u.Query()
+	q.Set("terraform-get", "1")
+	u.RawQuery = q.Encode()
+
+	header := http.Header{}
+	data, err := download(ctx, u.String(), header)
+	if err != nil {
+		return Module{}, err
+	}
+
+	location := header.Get("X-Terraform-Get")
+	if location == "" {
+		if m := terraformGetMeta.FindSubmatch(data); m != nil {
+			location = string(m[1])
+		}
+	}
+	if location == "" {
+		return fetchGitFallback(ctx, src, indirections)
+	}
+
+	// Relative locations are resolved against the page that returned them
+	if strings.HasPrefix(location, "/") || strings.HasPrefix(location, "./") || strings.HasPrefix(location, "../") {
+		ref, err := url.Parse(location)
+		if err != nil {
+			return Module{}, fmt.Errorf("invalid X-Terraform-Get %q: %w", location, err)
+		}
+		base, _ := url.Parse(src.URL)
+		location = base.ResolveReference(ref).String()
+	}
+
+	next, err := Parse(location)
+	if err != nil {
+		return Module{}, fmt.Errorf("X-Terraform-Get of %s: %w", src.URL, err)
+	}
+	if next.Getter == GETTER_LOCAL {
+		return Module{}, fmt.Errorf("X-Terraform-Get of %s must be a remote source, got %q", src.URL, location)
+	}
+	next.Subdir = path.Join(next.Subdir, src.Subdir)
+
+	return fetch(ctx, next, "", indirections+1)
+}
+
+// fetchGitFallback clones an HTTP URL that does not say where its module is.
+// Self-hosted git servers are only detected by go-getter with "git::" or a
+// ".git" suffix, but their repository pages were cloned by get_module before.
+func fetchGitFallback(ctx context.Context, src Source, indirections int) (Module, error) {
+	gitSrc := Source{Getter: GETTER_GIT, URL: src.URL, Subdir: src.Subdir}
+
+	module, err := fetch(ctx, gitSrc, "", indirections+1)
+	if err != nil {
+		return Module{}, fmt.Errorf("%s is not an archive, does not say where the module is (X-Terraform-Get), and cloning it as git failed: %v; name self-hosted git repositories with git:: or a .git suffix", src.URL, err)
+	}
+	return module, nil
+}
+
+// download GETs a URL, copying the response headers into header when it is not nil
+func download(ctx context.Context, rawURL string, header http.Header) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail := ""
+		if resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusUnauthorized {
+			detail = "; only publicly readable sources can be downloaded"
+		}
+		return nil, fmt.Errorf("download of %s failed: status=%d%s", rawURL, resp.StatusCode, detail)
+	}
+
+	for key, values := range resp.Header {
+		if header != nil {
+			header[key] = values
+		}
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, MAX_ARCHIVE_BYTES+1))
+	if err != nil {
+		return nil, fmt.Errorf("download of %s failed: %w", rawURL, err)
+	}
+	if len(data) > MAX_ARCHIVE_BYTES {
+		return nil, fmt.Errorf("download of %s is larger than %d bytes", rawURL, MAX_ARCHIVE_BYTES)
+	}
+
+	return data, nil
+}
+
+// extract unpacks an archive into fs. Entries that would land outside of the
+// archive root and links are skipped.
+func extract(fs billy.Filesystem, data []byte, archive string) error {
+	switch archive {
+	case "zip":
+		return extractZip(fs, data)
+	case "tar":
+		return extractTar(fs, bytes.NewReader(data))
+	case "tar.gz", "tgz":
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		return extractTar(fs, gz)
+	case "tar.bz2", "tbz2":
+		return extractTar(fs, bzip2.NewReader(bytes.NewReader(data)))
+	}
+
+	return fmt.Errorf("%s archives are not supported", archive)
+}
+
+func extractZip(fs billy.Filesystem, data []byte) error {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return err
+	}
+
+	written := int64(0)
+	for _, file := range reader.File {
+		name, ok := entryPath(file.Name)
+		if !ok {
+			continue
+		}
+
+		if file.FileInfo().IsDir() {
+			if err := fs.MkdirAll(name, 0o755); err != nil {
+				return err
+			}
+			continue
+		}
+		if !file.Mode().IsRegular() {
+			continue
+		}
+
+		rc, err := file.Open()
+		if err != nil {
+			return err
+		}
+		n, err := writeFile(fs, name, rc, MAX_ARCHIVE_BYTES-written)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+		written += n
+	}
+
+	return nil
+}
+
+func extractTar(fs billy.Filesystem, r io.Reader) error {
+	reader := tar.NewReader(r)
+
+	written := int64(0)
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		name, ok := entryPath(header.Name)
+		if !ok {
+			continue
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := fs.MkdirAll(name, 0o755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			n, err := writeFile(fs, name, reader, MAX_ARCHIVE_BYTES-written)
+			if err != nil {
+				return err
+			}
+			written += n
+		}
+	}
+}
+
+// writeFile copies at most limit bytes into a file, failing when there is more
+func writeFile(fs billy.Filesystem, name string, r io.Reader, limit int64) (int64, error) {
+	var buf bytes.Buffer
+	n, err := io.Copy(&buf, io.LimitReader(r, limit+1))
+	if err != nil {
+		return n, err
+	}
+	if n > limit {
+		return n, fmt.Errorf("archive is larger than %d bytes when extracted", MAX_ARCHIVE_BYTES)
+	}
+
+	return n, util.WriteFile(fs, name, buf.Bytes(), 0o644)
+}
+
+// entryPath cleans an archive entry name, rejecting names that leave the archive root
+func entryPath(name string) (string, bool) {
+	name = strings.ReplaceAll(name, "\\", "/")
+	if strings.HasPrefix(name, "/") {
+		return "", false
+	}
+	for _, part := range strings.Split(name, "/") {
+		if part == ".." {
+			return "", false
+		}
+	}
+
+	cleaned := path.Clean(name)
+	return cleaned, cleaned != "."
+}
+
+// gcsObjectURL turns a GCS JSON API address (www.googleapis.com/storage/v1/bucket/object)
+// into the public download URL of the object
+func gcsObjectURL(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	if !strings.EqualFold(u.Hostname(), "www.googleapis.com") {
+		return rawURL, nil
+	}
+
+	_, object, ok := strings.Cut(u.Path, "/storage/v1/")
+	if !ok || object == "" {
+		return "", fmt.Errorf("invalid GCS address: %s", rawURL)
+	}
+
+	return "https://storage.googleapis.com/" + object, nil
+}
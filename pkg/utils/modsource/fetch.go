@@ -0,0 +1,107 @@
+package modsource
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	"github.com/Yunsang-Jeong/terraform-mcp-server/pkg/utils/gitrepo"
+	"github.com/Yunsang-Jeong/terraform-mcp-server/pkg/utils/registry"
+
+	"github.com/Yunsang-Jeong/terraform-config-parser/pkg/filesystem"
+	"github.com/Yunsang-Jeong/terraform-config-parser/pkg/source"
+)
+
+// MAX_INDIRECTIONS bounds how many times a source may point to another one
+// (registry downloads and X-Terraform-Get redirects)
+const MAX_INDIRECTIONS = 5
+
+// Module is a fetched module source
+type Module struct {
+	FS filesystem.FileReader
+	// Root is the path of the module inside FS
+	Root string
+	// Source is what was fetched; for registry modules, the source the registry pointed to
+	Source Source
+	// Registry is the module address and Version the resolved version of registry sources
+	Registry string
+	Version  string
+	// Ref is the branch, tag or commit checked out of git sources
+	Ref gitrepo.Ref
+}
+
+// Fetch downloads a module source into memory. version is the version
+// constraint of registry sources, the latest version when empty.
+func Fetch(ctx context.Context, src Source, version string) (Module, error) {
+	return fetch(ctx, src, version, 0)
+}
+
+func fetch(ctx context.Context, src Source, version string, indirections int) (Module, error) {
+	if indirections > MAX_INDIRECTIONS {
+		return Module{}, fmt.Errorf("too many indirections fetching %s", src)
+	}
+
+	switch src.Getter {
+	case GETTER_LOCAL:
+		return Module{}, fmt.Errorf("local module source %q is relative to the calling module and cannot be fetched on its own; use get_local_module for directories on this machine", src.URL)
+	case GETTER_HG:
+		return Module{}, fmt.Errorf("mercurial sources are not supported: %s", src)
+	case GETTER_REGISTRY:
+		return fetchRegistry(ctx, src, version, indirections)
+	case GETTER_GIT:
+		checkout, err := gitrepo.Clone(ctx, src.URL, gitrepo.Options{
+			SourceConfig: source.SourceConfig{Ref: src.Ref, SubDir: src.Subdir},
+			Depth:        src.Depth,
+		})
+		if err != nil {
+			return Module{}, err
+		}
+		return Module{FS: checkout.FS, Root: checkout.Root, Source: src, Ref: checkout.Ref}, nil
+	case GETTER_HTTP, GETTER_S3, GETTER_GCS:
+		return fetchHTTP(ctx, src, indirections)
+	}
+
+	return Module{}, fmt.Errorf("unsupported getter %q", src.Getter)
+}
+
+// fetchRegistry resolves the version of a registry module and fetches the
+// source its download endpoint points to
+func fetchRegistry(ctx context.Context, src Source, version string, indirections int) (Module, error) {
+	addr, err := registry.ParseModuleAddress(src.URL)
+	if err != nil {
+		return Module{}, err
+	}
+
+	client, err := addr.Client()
+	if err != nil {
+		return Module{}, err
+	}
+
+	resolved, err := client.ResolveModuleVersion(ctx, addr.Namespace, addr.Name, addr.System, version)
+	if err != nil {
+		return Module{}, err
+	}
+
+	location, err := client.ModuleDownloadSource(ctx, addr.Namespace, addr.Name, addr.System, resolved.Version)
+	if err != nil {
+		return Module{}, err
+	}
+
+	download, err := Parse(location)
+	if err != nil {
+		return Module{}, fmt.Errorf("registry download location of %s: %w", addr, err)
+	}
+	if download.Getter == GETTER_REGISTRY || download.Getter == GETTER_LOCAL {
+		return Module{}, fmt.Errorf("registry download location of %s must be a remote source, got %q", addr, location)
+	}
+	download.Subdir = path.Join(download.Subdir, src.Subdir)
+
+	module, err := fetch(ctx, download, "", indirections+1)
+	if err != nil {
+		return Module{}, err
+	}
+	module.Registry = addr.String()
+	module.Version = resolved.Version
+
+	return module, nil
+}
@@ -0,0 +1,307 @@
+package modsource
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Yunsang-Jeong/terraform-mcp-server/pkg/utils"
+)
+
+const (
+	GETTER_LOCAL    = "local"
+	GETTER_REGISTRY = "registry"
+	GETTER_GIT      = "git"
+	GETTER_HG       = "hg"
+	GETTER_HTTP     = "http"
+	GETTER_S3       = "s3"
+	GETTER_GCS      = "gcs"
+)
+
+// forcedGetters are the getters a source can name with a "getter::" prefix
+var forcedGetters = []string{GETTER_GIT, GETTER_HG, GETTER_HTTP, GETTER_S3, GETTER_GCS}
+
+// ARCHIVE_TYPES are the archive formats modules can be downloaded as
+var ARCHIVE_TYPES = []string{"zip", "tar", "tar.gz", "tgz", "tar.bz2", "tbz2"}
+
+var (
+	forcedGetterPattern = regexp.MustCompile(`^([A-Za-z0-9]+)::(.+)$`)
+	// registryPattern matches [hostname/]namespace/name/system
+	registryPattern = regexp.MustCompile(`^(?:([^/]+)/)?([0-9A-Za-z](?:[0-9A-Za-z_-]{0,62}[0-9A-Za-z])?)/([0-9A-Za-z](?:[0-9A-Za-z_-]{0,62}[0-9A-Za-z])?)/([0-9a-z]{1,64})$`)
+	scpPattern      = regexp.MustCompile(`^([A-Za-z0-9._-]+)@([A-Za-z0-9.-]+):(.+)$`)
+	s3HostPattern   = regexp.MustCompile(`(^|\.)s3[.-]([a-z0-9-]+\.)?amazonaws\.com$`)
+)
+
+// gitHosts are hosted git services whose repository paths are detected without a "git::" prefix
+var gitHosts = []string{"github.com", "gitlab.com", "bitbucket.org"}
+
+// reservedHosts cannot be registry hosts: Terraform keeps them for their shorthands
+var reservedHosts = []string{"github.com", "bitbucket.org"}
+
+// Source is a Terraform module source address split the way go-getter reads it
+type Source struct {
+	// Getter is what fetches the source, one of the GETTER_* constants
+	Getter string `json:"getter"`
+	// URL is the address the getter fetches, without the subdirectory and
+	// the arguments meant for the getter. For registry sources it is the module address.
+	URL string `json:"url"`
+	// Subdir is the path of the module inside the fetched source ("//modules/x")
+	Subdir string `json:"subdir,omitempty"`
+	// Ref is the git branch, tag or commit ("?ref=v1.2.0")
+	Ref string `json:"ref,omitempty"`
+	// Depth limits the git history fetched ("?depth=1")
+	Depth int `json:"depth,omitempty"`
+	// Archive is the archive format, from "?archive=" or the URL's extension
+	Archive string `json:"archive,omitempty"`
+}
+
+// Parse reads a module source address as Terraform does: local paths,
+// registry addresses, "getter::" prefixes, the hosts go-getter detects
+// (github.com, bitbucket.org, git@host:path, S3 and GCS URLs), "//"
+// subdirectories and the ref, depth and archive arguments.
+func Parse(raw string) (Source, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return Source{}, fmt.Errorf("module source is empty")
+	}
+
+	if isLocal(raw) {
+		return Source{Getter: GETTER_LOCAL, URL: raw}, nil
+	}
+
+	getter, address := "", raw
+	if m := forcedGetterPattern.FindStringSubmatch(raw); m != nil {
+		getter, address = strings.ToLower(m[1]), m[2]
+		if !utils.IsInList(getter, forcedGetters) {
+			return Source{}, fmt.Errorf("unsupported getter %q in module source %q", getter, raw)
+		}
+	}
+
+	address, subdir := splitSubdir(address)
+	if strings.HasPrefix(subdir, "../") || subdir == ".." {
+		return Source{}, fmt.Errorf("subdirectory of module source %q must not leave the source", raw)
+	}
+
+	if getter == "" {
+		if m := registryPattern.FindStringSubmatch(address); m != nil && isRegistryHost(m[1]) {
+			return Source{Getter: GETTER_REGISTRY, URL: address, Subdir: subdir}, nil
+		}
+
+		var extra string
+		var err error
+		if getter, address, extra, err = detect(address); err != nil {
+			return Source{}, fmt.Errorf("%w: %q", err, raw)
+		}
+		subdir = path.Join(extra, subdir)
+	}
+
+	src := Source{Getter: getter, Subdir: subdir}
+	if err := src.setURL(address); err != nil {
+		return Source{}, fmt.Errorf("invalid module source %q: %w", raw, err)
+	}
+
+	return src, nil
+}
+
+// String writes the source back as an address with a forced getter
+func (s Source) String() string {
+	switch s.Getter {
+	case GETTER_LOCAL:
+		return s.URL
+	case GETTER_REGISTRY:
+		return joinSubdir(s.URL, s.Subdir)
+	}
+
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		return s.Getter + "::" + joinSubdir(s.URL, s.Subdir)
+	}
+	q := u.Query()
+	if s.Ref != "" {
+		q.Set("ref", s.Ref)
+	}
+	if s.Depth > 0 {
+		q.Set("depth", strconv.Itoa(s.Depth))
+	}
+	u.RawQuery = ""
+
+	address := joinSubdir(u.String(), s.Subdir)
+	if encoded := q.Encode(); encoded != "" {
+		address += "?" + encoded
+	}
+	return s.Getter + "::" + address
+}
+
+// setURL takes the getter arguments out of the URL's query
+func (s *Source) setURL(address string) error {
+	u, err := url.Parse(address)
+	if err != nil {
+		return err
+	}
+	if u.Scheme == "" || (u.Host == "" && u.Scheme != "file") {
+		return fmt.Errorf("%s sources need an absolute URL", s.Getter)
+	}
+
+	q := u.Query()
+	switch s.Getter {
+	case GETTER_GIT, GETTER_HG:
+		s.Ref = q.Get("ref")
+		q.Del("ref")
+		if depth := q.Get("depth"); depth != "" {
+			if s.Depth, err = strconv.Atoi(depth); err != nil || s.Depth < 0 {
+				return fmt.Errorf("invalid depth %q", depth)
+			}
+		}
+		q.Del("depth")
+	default:
+		s.Archive = q.Get("archive")
+		q.Del("archive")
+		if s.Archive != "" && !utils.IsInList(s.Archive, ARCHIVE_TYPES) {
+			return fmt.Errorf("unsupported archive %q: expected one of %s", s.Archive, strings.Join(ARCHIVE_TYPES, ", "))
+		}
+		if s.Archive == "" {
+			s.Archive = archiveType(u.Path)
+		}
+	}
+	u.RawQuery = q.Encode()
+
+	s.URL = u.String()
+	return nil
+}
+
+// detect picks the getter for an address without a "getter::" prefix, as
+// go-getter's detectors do. Path parts after a hosted repository
+// ("github.com/org/repo/modules/x") are returned as a subdirectory.
+func detect(address string) (string, string, string, error) {
+	if m := scpPattern.FindStringSubmatch(address); m != nil && !strings.Contains(address, "://") {
+		return GETTER_GIT, fmt.Sprintf("ssh://%s@%s/%s", m[1], m[2], strings.TrimPrefix(m[3], "/")), "", nil
+	}
+
+	if !strings.Contains(address, "://") {
+		host, _, _ := strings.Cut(address, "/")
+		switch {
+		case utils.IsInList(strings.ToLower(host), gitHosts):
+			return gitHostURL(address)
+		case s3HostPattern.MatchString(strings.ToLower(host)):
+			return GETTER_S3, "https://" + address, "", nil
+		case strings.EqualFold(host, "www.googleapis.com") && strings.Contains(address, "/storage/"):
+			return GETTER_GCS, "https://" + address, "", nil
+		}
+		return "", "", "", fmt.Errorf("module source must be a local path starting with ./ or ../, a registry address or a remote URL")
+	}
+
+	u, err := url.Parse(address)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "ssh", "git":
+		return GETTER_GIT, address, "", nil
+	case "http", "https":
+		// Repositories on hosted git services are cloned unless an archive is named
+		host := strings.ToLower(u.Hostname())
+		if archiveType(u.Path) == "" && u.Query().Get("archive") == "" && (utils.IsInList(host, gitHosts) || strings.HasSuffix(u.Path, ".git")) {
+			return GETTER_GIT, address, "", nil
+		}
+		if s3HostPattern.MatchString(host) {
+			return GETTER_S3, address, "", nil
+		}
+		return GETTER_HTTP, address, "", nil
+	case "s3":
+		return "", "", "", fmt.Errorf("s3:// addresses are not module sources; use s3::https://")
+	case "gs":
+		return "", "", "", fmt.Errorf("gs:// addresses are not module sources; use gcs::https://")
+	}
+
+	return "", "", "", fmt.Errorf("unsupported scheme %q in module source", u.Scheme)
+}
+
+// gitHostURL turns "github.com/org/repo/modules/x?ref=v1" into the https
+// clone URL of org/repo with the query, and the "modules/x" subdirectory
+func gitHostURL(address string) (string, string, string, error) {
+	address, query, _ := strings.Cut(address, "?")
+
+	parts := strings.Split(strings.TrimSuffix(address, "/"), "/")
+	if len(parts) < 3 {
+		return "", "", "", fmt.Errorf("%s sources need an owner and a repository", parts[0])
+	}
+
+	repo := strings.Join(parts[:3], "/")
+	if !strings.HasSuffix(repo, ".git") {
+		repo += ".git"
+	}
+
+	u := "https://" + repo
+	if query != "" {
+		u += "?" + query
+	}
+	return GETTER_GIT, u, strings.Join(parts[3:], "/"), nil
+}
+
+// splitSubdir separates the "//subdir" part of an address, keeping the query with the address
+func splitSubdir(address string) (string, string) {
+	address, query, hasQuery := strings.Cut(address, "?")
+
+	offset := 0
+	if i := strings.Index(address, "://"); i >= 0 {
+		offset = i + len("://")
+	}
+
+	subdir := ""
+	if i := strings.Index(address[offset:], "//"); i >= 0 {
+		subdir = path.Clean(strings.Trim(address[offset+i+2:], "/"))
+		if subdir == "." {
+			subdir = ""
+		}
+		address = address[:offset+i]
+	}
+
+	if hasQuery {
+		address += "?" + query
+	}
+	return address, subdir
+}
+
+func joinSubdir(address, subdir string) string {
+	if subdir == "" {
+		return address
+	}
+	return address + "//" + subdir
+}
+
+// archiveType is the archive format named by a path's extension, or empty
+func archiveType(p string) string {
+	p = strings.ToLower(p)
+
+	// Longest extensions first, so ".tar.gz" is not read as ".gz"
+	best := ""
+	for _, ext := range ARCHIVE_TYPES {
+		if strings.HasSuffix(p, "."+ext) && len(ext) > len(best) {
+			best = ext
+		}
+	}
+	return best
+}
+
+// isRegistryHost reports whether the first part of a four part address can be
+// a registry host. Terraform reserves github.com and bitbucket.org for their shorthands.
+func isRegistryHost(host string) bool {
+	if host == "" {
+		return true
+	}
+	host = strings.ToLower(host)
+	return (strings.Contains(host, ".") || strings.HasPrefix(host, "localhost")) && !utils.IsInList(host, reservedHosts)
+}
+
+func isLocal(raw string) bool {
+	for _, prefix := range []string{"./", "../", ".\\", "..\\"} {
+		if strings.HasPrefix(raw, prefix) {
+			return true
+		}
+	}
+	return raw == "." || raw == ".."
+}
@@ -0,0 +1,252 @@
+package modsource
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Yunsang-Jeong/terraform-mcp-server/pkg/utils/registry"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		source  string
+		want    Source
+		wantErr bool
+	}{
+		{source: "./modules/vpc", want: Source{Getter: GETTER_LOCAL, URL: "./modules/vpc"}},
+		{source: "terraform-aws-modules/vpc/aws", want: Source{Getter: GETTER_REGISTRY, URL: "terraform-aws-modules/vpc/aws"}},
+		{source: "app.terraform.io/acme/network/aws//modules/subnet", want: Source{Getter: GETTER_REGISTRY, URL: "app.terraform.io/acme/network/aws", Subdir: "modules/subnet"}},
+		{source: "gitlab.com/acme/network/aws", want: Source{Getter: GETTER_REGISTRY, URL: "gitlab.com/acme/network/aws"}},
+		{source: "github.com/user/repo", want: Source{Getter: GETTER_GIT, URL: "https://github.com/user/repo.git"}},
+		{source: "github.com/user/repo/modules/vpc?ref=v1.2.0", want: Source{Getter: GETTER_GIT, URL: "https://github.com/user/repo.git", Subdir: "modules/vpc", Ref: "v1.2.0"}},
+		{source: "git@github.com:user/repo.git", want: Source{Getter: GETTER_GIT, URL: "ssh://git@github.com/user/repo.git"}},
+		{source: "git@github.com:user/repo.git//modules/vpc?ref=main&depth=1", want: Source{Getter: GETTER_GIT, URL: "ssh://git@github.com/user/repo.git", Subdir: "modules/vpc", Ref: "main", Depth: 1}},
+		{source: "https://github.com/user/repo", want: Source{Getter: GETTER_GIT, URL: "https://github.com/user/repo"}},
+		{source: "https://gitlab.com/user/repo.git", want: Source{Getter: GETTER_GIT, URL: "https://gitlab.com/user/repo.git"}},
+		{source: "git::https://example.com/vpc.git?ref=v1.2.0", want: Source{Getter: GETTER_GIT, URL: "https://example.com/vpc.git", Ref: "v1.2.0"}},
+		{source: "https://example.com/vpc-module.zip", want: Source{Getter: GETTER_HTTP, URL: "https://example.com/vpc-module.zip", Archive: "zip"}},
+		{source: "https://example.com/vpc-module?archive=tar.gz", want: Source{Getter: GETTER_HTTP, URL: "https://example.com/vpc-module", Archive: "tar.gz"}},
+		{source: "https://example.com/vpc-module", want: Source{Getter: GETTER_HTTP, URL: "https://example.com/vpc-module"}},
+		{source: "https://example.com/vpc-module.tar.xz", want: Source{Getter: GETTER_HTTP, URL: "https://example.com/vpc-module.tar.xz"}},
+		{source: "bucket.s3-eu-west-1.amazonaws.com/vpc/module.tgz//modules/x", want: Source{Getter: GETTER_S3, URL: "https://bucket.s3-eu-west-1.amazonaws.com/vpc/module.tgz", Subdir: "modules/x", Archive: "tgz"}},
+		{source: "gcs::https://www.googleapis.com/storage/v1/modules/vpc.zip", want: Source{Getter: GETTER_GCS, URL: "https://www.googleapis.com/storage/v1/modules/vpc.zip", Archive: "zip"}},
+		{source: "", wantErr: true},
+		{source: "git@github.com", wantErr: true},
+		{source: "not-a-valid-url", wantErr: true},
+		{source: "svn::https://example.com/repo", wantErr: true},
+		{source: "s3://bucket/module.zip", wantErr: true},
+		{source: "git::https://example.com/repo.git//../other", wantErr: true},
+		{source: "git::https://example.com/repo.git?depth=-1", wantErr: true},
+		{source: "https://example.com/vpc-module?archive=tar.xz", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.source)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Parse(%q) expected error but got %+v", tt.source, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Parse(%q) unexpected error: %v", tt.source, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Parse(%q) = %+v, want %+v", tt.source, got, tt.want)
+		}
+	}
+}
+
+func TestSourceString(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{source: "terraform-aws-modules/vpc/aws//modules/vpc-endpoints", want: "terraform-aws-modules/vpc/aws//modules/vpc-endpoints"},
+		{source: "github.com/user/repo/modules/vpc?ref=v1.2.0", want: "git::https://github.com/user/repo.git//modules/vpc?ref=v1.2.0"},
+		{source: "https://example.com/vpc-module.zip", want: "http::https://example.com/vpc-module.zip"},
+	}
+
+	for _, tt := range tests {
+		src, err := Parse(tt.source)
+		if err != nil {
+			t.Errorf("Parse(%q) unexpected error: %v", tt.source, err)
+			continue
+		}
+		if got := src.String(); got != tt.want {
+			t.Errorf("Source.String() = %q, want %q", got, tt.want)
+		}
+
+		// The written address parses back to the same source
+		again, err := Parse(src.String())
+		if err != nil || again != src {
+			t.Errorf("Parse(%q) = %+v, %v, want %+v", src.String(), again, err, src)
+		}
+	}
+}
+
+func TestEntryPath(t *testing.T) {
+	tests := []struct {
+		name   string
+		want   string
+		wantOK bool
+	}{
+		{name: "main.tf", want: "main.tf", wantOK: true},
+		{name: "./modules/vpc/main.tf", want: "modules/vpc/main.tf", wantOK: true},
+		{name: "modules\\vpc\\main.tf", want: "modules/vpc/main.tf", wantOK: true},
+		{name: "../main.tf", wantOK: false},
+		{name: "modules/../../main.tf", wantOK: false},
+		{name: "/etc/passwd", wantOK: false},
+		{name: "./", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		got, ok := entryPath(tt.name)
+		if ok != tt.wantOK || (ok && got != tt.want) {
+			t.Errorf("entryPath(%q) = %q, %v, want %q, %v", tt.name, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestFetch(t *testing.T) {
+	archive := zipArchive(t, map[string]string{
+		"main.tf":                "variable \"name\" {}\n",
+		"modules/subnet/main.tf": "variable \"cidr\" {}\n",
+		"../escape.tf":           "variable \"escape\" {}\n",
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/modules/acme/network/aws/versions", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"modules":[{"versions":[{"version":"1.0.0"},{"version":"1.1.0"},{"version":"2.0.0"}]}]}`))
+	})
+	mux.HandleFunc("/v1/modules/acme/network/aws/1.1.0/download", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Terraform-Get", "/landing")
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("/landing", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("terraform-get") != "1" {
+			t.Errorf("landing page requested without terraform-get=1: %s", r.URL.RawQuery)
+		}
+		w.Write([]byte(`<html><head><meta name="terraform-get" content="./network.zip"></head></html>`))
+	})
+	mux.HandleFunc("/network.zip", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(archive)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	if err := registry.Configure(registry.Config{Hosts: map[string]string{registry.DEFAULT_REGISTRY_HOST: srv.URL}}); err != nil {
+		t.Fatalf("registry.Configure() unexpected error: %v", err)
+	}
+	defer registry.Configure(registry.Config{})
+
+	ctx := context.Background()
+
+	src, err := Parse("acme/network/aws//modules/subnet")
+	if err != nil {
+		t.Fatalf("Parse() unexpected error: %v", err)
+	}
+	module, err := Fetch(ctx, src, "~> 1.0")
+	if err != nil {
+		t.Fatalf("Fetch() unexpected error: %v", err)
+	}
+	if module.Version != "1.1.0" || module.Registry != registry.DEFAULT_REGISTRY_HOST+"/acme/network/aws" {
+		t.Errorf("Fetch() registry = %s %s, want acme/network/aws 1.1.0", module.Registry, module.Version)
+	}
+	if module.Source.Getter != GETTER_HTTP || module.Source.URL != srv.URL+"/network.zip" || module.Root != "modules/subnet" {
+		t.Errorf("Fetch() source = %+v, root = %s", module.Source, module.Root)
+	}
+
+	data, err := module.FS.ReadFile("modules/subnet/main.tf")
+	if err != nil {
+		t.Fatalf("Fetch() module is missing modules/subnet/main.tf: %v", err)
+	}
+	if string(data) != "variable \"cidr\" {}\n" {
+		t.Errorf("modules/subnet/main.tf = %q", data)
+	}
+	if _, err := module.FS.ReadFile("escape.tf"); err == nil {
+		t.Error("Fetch() extracted an entry outside of the archive root")
+	}
+
+	src, _ = Parse(srv.URL + "/network.zip//modules/missing")
+	if _, err := Fetch(ctx, src, ""); err == nil {
+		t.Error("Fetch() expected error for a missing subdirectory")
+	}
+
+	src, _ = Parse(srv.URL + "/nothing-here")
+	if _, err := Fetch(ctx, src, ""); err == nil {
+		t.Error("Fetch() expected error for a page without X-Terraform-Get")
+	}
+}
+
+func TestFetch_GitFallback(t *testing.T) {
+	// A self-hosted git server: the repository page has no X-Terraform-Get
+	gitRequests := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/group/repo", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<html><head><title>group / repo</title></head></html>`))
+	})
+	gitHandler := func(w http.ResponseWriter, r *http.Request) {
+		gitRequests++
+		http.Error(w, "not found", http.StatusNotFound)
+	}
+	mux.HandleFunc("/group/repo/info/refs", gitHandler)
+	mux.HandleFunc("/group/missing/info/refs", gitHandler)
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	src, err := Parse(srv.URL + "/group/repo")
+	if err != nil {
+		t.Fatalf("Parse() unexpected error: %v", err)
+	}
+	if src.Getter != GETTER_HTTP {
+		t.Fatalf("Parse() getter = %s, want %s", src.Getter, GETTER_HTTP)
+	}
+
+	_, err = Fetch(context.Background(), src, "")
+	if err == nil {
+		t.Fatal("Fetch() expected error for a repository that cannot be cloned")
+	}
+	if gitRequests == 0 {
+		t.Error("Fetch() did not try to clone the page as a git repository")
+	}
+	if !strings.Contains(err.Error(), "git::") {
+		t.Errorf("Fetch() error = %v, want a hint to use git::", err)
+	}
+
+	// A page that cannot be downloaded is not cloned
+	gitRequests = 0
+	src, _ = Parse(srv.URL + "/group/missing")
+	_, err = Fetch(context.Background(), src, "")
+	if err == nil || !strings.Contains(err.Error(), "status=404") {
+		t.Errorf("Fetch() error = %v, want the failed download", err)
+	}
+	if gitRequests != 0 {
+		t.Errorf("Fetch() tried to clone a page that failed to download")
+	}
+}
+
+func zipArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatalf("zip.Create(%q) unexpected error: %v", name, err)
+		}
+		f.Write([]byte(content))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("zip.Close() unexpected error: %v", err)
+	}
+
+	return buf.Bytes()
+}
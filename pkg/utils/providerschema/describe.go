@@ -0,0 +1,158 @@
+package providerschema
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Attribute is the description of a schema attribute. Type is written as in
+// a variable type constraint; nested attributes have a nesting mode instead.
+type Attribute struct {
+	Name        string      `json:"name"`
+	Type        string      `json:"type,omitempty"`
+	NestingMode string      `json:"nesting_mode,omitempty"`
+	Required    bool        `json:"required,omitempty"`
+	Optional    bool        `json:"optional,omitempty"`
+	Computed    bool        `json:"computed,omitempty"`
+	Sensitive   bool        `json:"sensitive,omitempty"`
+	WriteOnly   bool        `json:"write_only,omitempty"`
+	Deprecated  bool        `json:"deprecated,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Attributes  []Attribute `json:"attributes,omitempty"`
+}
+
+// Block is the description of a schema block and what it contains
+type Block struct {
+	Name        string      `json:"name,omitempty"`
+	NestingMode string      `json:"nesting_mode,omitempty"`
+	MinItems    uint64      `json:"min_items,omitempty"`
+	MaxItems    uint64      `json:"max_items,omitempty"`
+	Deprecated  bool        `json:"deprecated,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Attributes  []Attribute `json:"attributes,omitempty"`
+	Blocks      []Block     `json:"blocks,omitempty"`
+}
+
+// Element is what a path inside a block points to: an attribute or a nested block
+type Element struct {
+	Path      string     `json:"path"`
+	Attribute *Attribute `json:"attribute,omitempty"`
+	Block     *Block     `json:"block,omitempty"`
+}
+
+// Describe converts a schema block, with its nested blocks and attributes, to its description
+func Describe(block *tfjson.SchemaBlock) Block {
+	desc := Block{
+		Deprecated:  block.Deprecated,
+		Description: block.Description,
+		Attributes:  describeAttributes(block.Attributes),
+		Blocks:      []Block{},
+	}
+
+	for _, name := range sortedKeys(block.NestedBlocks) {
+		desc.Blocks = append(desc.Blocks, describeBlockType(name, block.NestedBlocks[name]))
+	}
+
+	return desc
+}
+
+// Find resolves a dotted path ("rule.expiration.days") inside a block
+func Find(block *tfjson.SchemaBlock, path string) (Element, error) {
+	parts := strings.Split(path, ".")
+
+	for i, part := range parts {
+		rest := strings.Join(parts[:i+1], ".")
+
+		if attr, ok := block.Attributes[part]; ok {
+			return findAttribute(part, attr, parts[i+1:], rest)
+		}
+
+		blockType, ok := block.NestedBlocks[part]
+		if !ok || blockType.Block == nil {
+			return Element{}, fmt.Errorf("%s not found; available: %s", rest, strings.Join(append(sortedKeys(block.Attributes), sortedKeys(block.NestedBlocks)...), ", "))
+		}
+		if i == len(parts)-1 {
+			desc := describeBlockType(part, blockType)
+			return Element{Path: path, Block: &desc}, nil
+		}
+		block = blockType.Block
+	}
+
+	return Element{}, fmt.Errorf("invalid path: %q", path)
+}
+
+// findAttribute resolves the rest of a path inside a nested attribute
+func findAttribute(name string, attr *tfjson.SchemaAttribute, rest []string, path string) (Element, error) {
+	for _, part := range rest {
+		if attr.AttributeNestedType == nil {
+			return Element{}, fmt.Errorf("%s has no nested attributes", path)
+		}
+
+		nested, ok := attr.AttributeNestedType.Attributes[part]
+		if !ok {
+			return Element{}, fmt.Errorf("%s.%s not found; available: %s", path, part, strings.Join(sortedKeys(attr.AttributeNestedType.Attributes), ", "))
+		}
+		name, attr, path = part, nested, path+"."+part
+	}
+
+	desc := describeAttribute(name, attr)
+	return Element{Path: path, Attribute: &desc}, nil
+}
+
+func describeBlockType(name string, blockType *tfjson.SchemaBlockType) Block {
+	desc := Block{}
+	if blockType.Block != nil {
+		desc = Describe(blockType.Block)
+	}
+
+	desc.Name = name
+	desc.NestingMode = string(blockType.NestingMode)
+	desc.MinItems = blockType.MinItems
+	desc.MaxItems = blockType.MaxItems
+
+	return desc
+}
+
+func describeAttributes(attrs map[string]*tfjson.SchemaAttribute) []Attribute {
+	descs := []Attribute{}
+	for _, name := range sortedKeys(attrs) {
+		descs = append(descs, describeAttribute(name, attrs[name]))
+	}
+	return descs
+}
+
+func describeAttribute(name string, attr *tfjson.SchemaAttribute) Attribute {
+	desc := Attribute{
+		Name:        name,
+		Required:    attr.Required,
+		Optional:    attr.Optional,
+		Computed:    attr.Computed,
+		Sensitive:   attr.Sensitive,
+		WriteOnly:   attr.WriteOnly,
+		Deprecated:  attr.Deprecated,
+		Description: attr.Description,
+	}
+
+	if attr.AttributeNestedType != nil {
+		desc.NestingMode = string(attr.AttributeNestedType.NestingMode)
+		desc.Attributes = describeAttributes(attr.AttributeNestedType.Attributes)
+	} else if attr.AttributeType != cty.NilType {
+		desc.Type = typeexpr.TypeString(attr.AttributeType)
+	}
+
+	return desc
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,104 @@
+package providerschema
+
+import (
+	"github.com/Yunsang-Jeong/terraform-mcp-server/pkg/utils/docschema"
+
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// docTypes maps the type hints of provider docs to the types values are checked
+// against. Element types are not documented, so collections accept any element.
+var docTypes = map[string]cty.Type{
+	"string": cty.String,
+	"number": cty.Number,
+	"bool":   cty.Bool,
+	"list":   cty.List(cty.DynamicPseudoType),
+	"set":    cty.Set(cty.DynamicPseudoType),
+	"map":    cty.Map(cty.DynamicPseudoType),
+}
+
+// FromDoc builds a schema block from what a registry document says, for blocks
+// missing from the loaded schemas. Docs do not say where a nested block is
+// used, so a documented block is allowed wherever an argument of its name is,
+// and at the top level when no argument names it. A block whose arguments are
+// not documented has a nil Block, and its contents are not checked.
+func FromDoc(schema docschema.Schema) *tfjson.SchemaBlock {
+	documented := map[string][]docschema.Argument{}
+	for _, block := range schema.Blocks {
+		documented[block.Name] = block.Arguments
+	}
+
+	root := docBlock(schema.Arguments, documented, map[string]bool{})
+
+	referenced := map[string]bool{}
+	for _, arg := range schema.Arguments {
+		referenced[arg.Name] = true
+	}
+	for _, block := range schema.Blocks {
+		for _, arg := range block.Arguments {
+			referenced[arg.Name] = true
+		}
+	}
+	for _, block := range schema.Blocks {
+		if !referenced[block.Name] {
+			root.NestedBlocks[block.Name] = &tfjson.SchemaBlockType{
+				NestingMode: tfjson.SchemaNestingModeList,
+				Block:       docBlock(block.Arguments, documented, map[string]bool{block.Name: true}),
+			}
+		}
+	}
+
+	if len(schema.Timeouts) > 0 {
+		timeouts := &tfjson.SchemaBlock{Attributes: map[string]*tfjson.SchemaAttribute{}}
+		for _, timeout := range schema.Timeouts {
+			timeouts.Attributes[timeout.Operation] = &tfjson.SchemaAttribute{AttributeType: cty.String, Optional: true}
+		}
+		root.NestedBlocks["timeouts"] = &tfjson.SchemaBlockType{NestingMode: tfjson.SchemaNestingModeSingle, Block: timeouts}
+	}
+
+	return root
+}
+
+// docBlock converts documented arguments, turning those documented as blocks
+// into nested blocks. parents guards against blocks documented as containing themselves.
+func docBlock(args []docschema.Argument, documented map[string][]docschema.Argument, parents map[string]bool) *tfjson.SchemaBlock {
+	block := &tfjson.SchemaBlock{
+		Attributes:   map[string]*tfjson.SchemaAttribute{},
+		NestedBlocks: map[string]*tfjson.SchemaBlockType{},
+	}
+
+	for _, arg := range args {
+		if arg.Type != "block" {
+			attrType, ok := docTypes[arg.Type]
+			if !ok {
+				attrType = cty.DynamicPseudoType
+			}
+			block.Attributes[arg.Name] = &tfjson.SchemaAttribute{
+				AttributeType: attrType,
+				Required:      arg.Required,
+				Optional:      !arg.Required,
+				Sensitive:     arg.Sensitive,
+				Deprecated:    arg.Deprecated,
+				Description:   arg.Description,
+			}
+			continue
+		}
+
+		blockType := &tfjson.SchemaBlockType{NestingMode: tfjson.SchemaNestingModeList}
+		if arg.Required {
+			blockType.MinItems = 1
+		}
+		if nested, ok := documented[arg.Name]; ok && !parents[arg.Name] {
+			parents[arg.Name] = true
+			blockType.Block = docBlock(nested, documented, parents)
+			delete(parents, arg.Name)
+		}
+		if blockType.Block != nil {
+			blockType.Block.Deprecated = arg.Deprecated
+		}
+		block.NestedBlocks[arg.Name] = blockType
+	}
+
+	return block
+}
@@ -0,0 +1,178 @@
+package providerschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Yunsang-Jeong/terraform-mcp-server/pkg/utils/registry"
+
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+const (
+	KIND_PROVIDER  = "provider"
+	KIND_RESOURCE  = "resource"
+	KIND_DATA      = "data"
+	KIND_EPHEMERAL = "ephemeral"
+	KIND_LIST      = "list"
+)
+
+// KINDS are the block kinds a provider schema describes
+var KINDS = []string{KIND_PROVIDER, KIND_RESOURCE, KIND_DATA, KIND_EPHEMERAL, KIND_LIST}
+
+// Store indexes the provider schemas printed by `terraform providers schema -json`
+type Store struct {
+	mu        sync.RWMutex
+	providers map[registry.ProviderAddress]*tfjson.ProviderSchema
+}
+
+// ProviderSummary describes a provider schema held by a Store
+type ProviderSummary struct {
+	Address            string `json:"address"`
+	Resources          int    `json:"resources"`
+	DataSources        int    `json:"data_sources"`
+	EphemeralResources int    `json:"ephemeral_resources,omitempty"`
+	ListResources      int    `json:"list_resources,omitempty"`
+	Functions          int    `json:"functions,omitempty"`
+}
+
+var defaultStore = NewStore()
+
+// Default returns the store shared by the tools
+func Default() *Store {
+	return defaultStore
+}
+
+func NewStore() *Store {
+	return &Store{providers: map[registry.ProviderAddress]*tfjson.ProviderSchema{}}
+}
+
+// Load adds the providers of a `terraform providers schema -json` document,
+// replacing providers already loaded under the same address, and returns
+// the addresses it loaded
+func (s *Store) Load(data []byte) ([]string, error) {
+	var schemas tfjson.ProviderSchemas
+	if err := json.Unmarshal(data, &schemas); err != nil {
+		return nil, fmt.Errorf("invalid provider schema json: %w", err)
+	}
+
+	// Every address is checked before any is stored, so a rejected document loads nothing
+	parsed := map[registry.ProviderAddress]*tfjson.ProviderSchema{}
+	for source, schema := range schemas.Schemas {
+		addr, err := registry.ParseProviderAddress(source, "")
+		if err != nil {
+			return nil, err
+		}
+		parsed[normalizeAddress(addr)] = schema
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	loaded := []string{}
+	for addr, schema := range parsed {
+		s.providers[addr] = schema
+		loaded = append(loaded, addr.String())
+	}
+	sort.Strings(loaded)
+
+	return loaded, nil
+}
+
+// LoadFile loads a `terraform providers schema -json` output file
+func (s *Store) LoadFile(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Load(data)
+}
+
+// Providers summarises the loaded providers
+func (s *Store) Providers() []ProviderSummary {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	summaries := []ProviderSummary{}
+	for addr, schema := range s.providers {
+		summaries = append(summaries, ProviderSummary{
+			Address:            addr.String(),
+			Resources:          len(schema.ResourceSchemas),
+			DataSources:        len(schema.DataSourceSchemas),
+			EphemeralResources: len(schema.EphemeralResourceSchemas),
+			ListResources:      len(schema.ListResourceSchemas),
+			Functions:          len(schema.Functions),
+		})
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Address < summaries[j].Address })
+
+	return summaries
+}
+
+// Lookup finds the schema of a block. The provider matches on its full
+// address, then on namespace and name under any host, since the same provider
+// is printed under registry.opentofu.org by OpenTofu. typeName may omit the
+// provider prefix ("s3_bucket"); it is ignored for the provider kind.
+func (s *Store) Lookup(provider registry.ProviderAddress, kind, typeName string) (*tfjson.SchemaBlock, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	schema, ok := s.provider(normalizeAddress(provider))
+	if !ok {
+		return nil, false
+	}
+
+	var schemas map[string]*tfjson.Schema
+	switch kind {
+	case KIND_PROVIDER:
+		if schema.ConfigSchema == nil || schema.ConfigSchema.Block == nil {
+			return nil, false
+		}
+		return schema.ConfigSchema.Block, true
+	case KIND_RESOURCE:
+		schemas = schema.ResourceSchemas
+	case KIND_DATA:
+		schemas = schema.DataSourceSchemas
+	case KIND_EPHEMERAL:
+		schemas = schema.EphemeralResourceSchemas
+	case KIND_LIST:
+		schemas = schema.ListResourceSchemas
+	default:
+		return nil, false
+	}
+
+	for _, name := range []string{typeName, provider.Name + "_" + typeName} {
+		if block, ok := schemas[name]; ok && block.Block != nil {
+			return block.Block, true
+		}
+	}
+
+	return nil, false
+}
+
+func (s *Store) provider(addr registry.ProviderAddress) (*tfjson.ProviderSchema, bool) {
+	if schema, ok := s.providers[addr]; ok {
+		return schema, true
+	}
+
+	for candidate, schema := range s.providers {
+		if candidate.Namespace == addr.Namespace && candidate.Name == addr.Name {
+			return schema, true
+		}
+	}
+
+	return nil, false
+}
+
+func normalizeAddress(addr registry.ProviderAddress) registry.ProviderAddress {
+	return registry.ProviderAddress{
+		Host:      strings.ToLower(addr.Host),
+		Namespace: strings.ToLower(addr.Namespace),
+		Name:      strings.ToLower(addr.Name),
+	}
+}
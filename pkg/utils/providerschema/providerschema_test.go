@@ -0,0 +1,134 @@
+package providerschema
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Yunsang-Jeong/terraform-mcp-server/pkg/utils/registry"
+)
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+
+	store := NewStore()
+	loaded, err := store.LoadFile("testdata/schema.json")
+	if err != nil {
+		t.Fatalf("LoadFile() unexpected error: %v", err)
+	}
+	if want := []string{"registry.terraform.io/hashicorp/aws"}; !reflect.DeepEqual(loaded, want) {
+		t.Fatalf("LoadFile() = %v, want %v", loaded, want)
+	}
+
+	return store
+}
+
+func TestStoreLookup(t *testing.T) {
+	store := newTestStore(t)
+
+	tests := []struct {
+		provider registry.ProviderAddress
+		kind     string
+		name     string
+		found    bool
+	}{
+		{registry.ProviderAddress{Host: "registry.terraform.io", Namespace: "hashicorp", Name: "aws"}, KIND_RESOURCE, "aws_s3_bucket", true},
+		{registry.ProviderAddress{Host: "registry.terraform.io", Namespace: "hashicorp", Name: "aws"}, KIND_RESOURCE, "s3_bucket", true},
+		{registry.ProviderAddress{Host: "registry.opentofu.org", Namespace: "hashicorp", Name: "aws"}, KIND_DATA, "aws_ami", true},
+		{registry.ProviderAddress{Host: "registry.terraform.io", Namespace: "hashicorp", Name: "aws"}, KIND_PROVIDER, "", true},
+		{registry.ProviderAddress{Host: "registry.terraform.io", Namespace: "hashicorp", Name: "aws"}, KIND_DATA, "aws_s3_bucket", false},
+		{registry.ProviderAddress{Host: "registry.terraform.io", Namespace: "hashicorp", Name: "google"}, KIND_RESOURCE, "google_compute_instance", false},
+	}
+
+	for _, tt := range tests {
+		if _, found := store.Lookup(tt.provider, tt.kind, tt.name); found != tt.found {
+			t.Errorf("Lookup(%s, %s, %s) found = %v, want %v", tt.provider, tt.kind, tt.name, found, tt.found)
+		}
+	}
+}
+
+func TestStoreProviders(t *testing.T) {
+	store := newTestStore(t)
+
+	want := []ProviderSummary{{Address: "registry.terraform.io/hashicorp/aws", Resources: 2, DataSources: 1}}
+	if got := store.Providers(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Providers() = %+v, want %+v", got, want)
+	}
+}
+
+func TestStoreLoad_Invalid(t *testing.T) {
+	if _, err := NewStore().Load([]byte("{")); err == nil {
+		t.Error("Load() expected error for invalid json")
+	}
+}
+
+func TestStoreLoad_InvalidAddress(t *testing.T) {
+	store := NewStore()
+	data := `{
+  "format_version": "1.0",
+  "provider_schemas": {
+    "registry.terraform.io/hashicorp/aws": {},
+    "registry.terraform.io/hashicorp/google": {},
+    "registry.terraform.io/hashicorp/random": {},
+    "registry.terraform.io//broken": {}
+  }
+}`
+
+	if _, err := store.Load([]byte(data)); err == nil {
+		t.Fatal("Load() expected error for invalid provider address")
+	}
+	if got := store.Providers(); len(got) != 0 {
+		t.Errorf("Providers() after rejected Load() = %+v, want none", got)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	store := newTestStore(t)
+	block, _ := store.Lookup(registry.ProviderAddress{Host: "registry.terraform.io", Namespace: "hashicorp", Name: "aws"}, KIND_RESOURCE, "aws_s3_bucket")
+
+	desc := Describe(block)
+
+	wantAttrs := []Attribute{
+		{Name: "acl", Type: "string", Optional: true, Deprecated: true},
+		{Name: "bucket", Type: "string", Optional: true, Computed: true},
+		{Name: "tags", Type: "map(string)", Optional: true},
+	}
+	if !reflect.DeepEqual(desc.Attributes, wantAttrs) {
+		t.Errorf("Describe() attributes = %+v, want %+v", desc.Attributes, wantAttrs)
+	}
+
+	if len(desc.Blocks) != 1 || desc.Blocks[0].Name != "lifecycle_rule" || desc.Blocks[0].NestingMode != "list" || desc.Blocks[0].Blocks[0].MaxItems != 1 {
+		t.Errorf("Describe() blocks = %+v", desc.Blocks)
+	}
+}
+
+func TestFind(t *testing.T) {
+	store := newTestStore(t)
+	aws := registry.ProviderAddress{Host: "registry.terraform.io", Namespace: "hashicorp", Name: "aws"}
+	bucket, _ := store.Lookup(aws, KIND_RESOURCE, "aws_s3_bucket")
+	db, _ := store.Lookup(aws, KIND_RESOURCE, "aws_db_instance")
+
+	element, err := Find(bucket, "lifecycle_rule.expiration.days")
+	if err != nil || element.Attribute == nil || element.Attribute.Type != "number" {
+		t.Errorf("Find(lifecycle_rule.expiration.days) = %+v, %v", element, err)
+	}
+
+	element, err = Find(bucket, "lifecycle_rule.expiration")
+	if err != nil || element.Block == nil || element.Block.MaxItems != 1 {
+		t.Errorf("Find(lifecycle_rule.expiration) = %+v, %v", element, err)
+	}
+
+	element, err = Find(db, "master_user_secret.kms_key_id")
+	if err != nil || element.Attribute == nil || !element.Attribute.Computed {
+		t.Errorf("Find(master_user_secret.kms_key_id) = %+v, %v", element, err)
+	}
+
+	element, err = Find(db, "password")
+	if err != nil || !element.Attribute.Sensitive {
+		t.Errorf("Find(password) = %+v, %v", element, err)
+	}
+
+	if _, err := Find(bucket, "lifecycle_rule.transition"); err == nil || !strings.Contains(err.Error(), "available: enabled, expiration") {
+		t.Errorf("Find(lifecycle_rule.transition) error = %v", err)
+	}
+}
@@ -0,0 +1,345 @@
+package providerschema
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Yunsang-Jeong/terraform-mcp-server/pkg/utils"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+const (
+	SEVERITY_ERROR   = "error"
+	SEVERITY_WARNING = "warning"
+)
+
+// blockLabels is the number of labels of the top-level blocks checked against a schema
+var blockLabels = map[string]int{
+	KIND_PROVIDER:  1,
+	KIND_RESOURCE:  2,
+	KIND_DATA:      2,
+	KIND_EPHEMERAL: 2,
+}
+
+// metaArguments are handled by Terraform itself, so provider schemas do not describe them
+var metaArguments = map[string][]string{
+	KIND_PROVIDER:  {"alias", "version"},
+	KIND_RESOURCE:  {"count", "for_each", "provider", "depends_on"},
+	KIND_DATA:      {"count", "for_each", "provider", "depends_on"},
+	KIND_EPHEMERAL: {"count", "for_each", "provider", "depends_on"},
+}
+
+var metaBlocks = map[string][]string{
+	KIND_RESOURCE:  {"lifecycle", "provisioner", "connection"},
+	KIND_DATA:      {"lifecycle"},
+	KIND_EPHEMERAL: {"lifecycle"},
+}
+
+// configBlocks are the other top-level blocks of a configuration, which are not checked
+var configBlocks = []string{"terraform", "variable", "output", "locals", "module", "moved", "import", "removed", "check"}
+
+// Diagnostic is a problem found in a configuration. Address is the top-level
+// block it is in ("resource.aws_s3_bucket.this") and Path the argument or
+// nested block inside it ("lifecycle_rule.expiration.days").
+type Diagnostic struct {
+	Severity string `json:"severity"`
+	Summary  string `json:"summary"`
+	Detail   string `json:"detail,omitempty"`
+	Address  string `json:"address,omitempty"`
+	Path     string `json:"path,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+}
+
+// SchemaFunc returns the schema of a provider, resource, data source or
+// ephemeral resource type, or why it has none
+type SchemaFunc func(kind, typeName string) (*tfjson.SchemaBlock, error)
+
+type validator struct {
+	schemaOf    SchemaFunc
+	schemas     map[string]*tfjson.SchemaBlock
+	errors      map[string]error
+	addresses   map[string]bool
+	diagnostics []Diagnostic
+}
+
+// Validate parses an HCL configuration and checks its provider, resource, data
+// and ephemeral blocks against their schemas: unknown block types, unknown and
+// missing required arguments, nested block names and counts, and the types of
+// values that do not depend on anything else. Diagnostics are in source order.
+func Validate(src []byte, filename string, schemaOf SchemaFunc) []Diagnostic {
+	v := &validator{
+		schemaOf:    schemaOf,
+		schemas:     map[string]*tfjson.SchemaBlock{},
+		errors:      map[string]error{},
+		addresses:   map[string]bool{},
+		diagnostics: []Diagnostic{},
+	}
+
+	file, diags := hclsyntax.ParseConfig(src, filename, hcl.InitialPos)
+	for _, diag := range diags {
+		severity := SEVERITY_ERROR
+		if diag.Severity == hcl.DiagWarning {
+			severity = SEVERITY_WARNING
+		}
+		v.add(severity, diag.Summary, diag.Detail, "", "", diag.Subject)
+	}
+	if diags.HasErrors() {
+		return v.diagnostics
+	}
+
+	body := file.Body.(*hclsyntax.Body)
+	for _, attr := range body.Attributes {
+		v.add(SEVERITY_ERROR, "Unsupported argument", fmt.Sprintf("An argument named %q is not expected at the top level.", attr.Name), "", "", attr.NameRange.Ptr())
+	}
+	for _, block := range body.Blocks {
+		v.topLevel(block)
+	}
+
+	sort.SliceStable(v.diagnostics, func(i, j int) bool {
+		if v.diagnostics[i].Line != v.diagnostics[j].Line {
+			return v.diagnostics[i].Line < v.diagnostics[j].Line
+		}
+		return v.diagnostics[i].Column < v.diagnostics[j].Column
+	})
+
+	return v.diagnostics
+}
+
+func (v *validator) topLevel(block *hclsyntax.Block) {
+	kind := block.Type
+
+	labels, checked := blockLabels[kind]
+	if !checked {
+		if !utils.IsInList(kind, configBlocks) {
+			detail := fmt.Sprintf("Blocks of type %q are not expected here.", kind) + suggestion(kind, append(configBlocks, KIND_PROVIDER, KIND_RESOURCE, KIND_DATA, KIND_EPHEMERAL))
+			v.add(SEVERITY_ERROR, "Unsupported block type", detail, "", "", block.TypeRange.Ptr())
+		}
+		return
+	}
+	if len(block.Labels) != labels {
+		v.add(SEVERITY_ERROR, "Wrong number of labels", fmt.Sprintf("A %s block has %d labels (got %d).", kind, labels, len(block.Labels)), "", "", block.DefRange().Ptr())
+		return
+	}
+
+	address := strings.Join(append([]string{kind}, block.Labels...), ".")
+	if kind != KIND_PROVIDER {
+		if v.addresses[address] {
+			v.add(SEVERITY_ERROR, "Duplicate "+kind, fmt.Sprintf("%s is declared more than once.", address), address, "", block.DefRange().Ptr())
+		}
+		v.addresses[address] = true
+	}
+
+	schema, err := v.schema(kind, block.Labels[0])
+	if err != nil {
+		v.add(SEVERITY_ERROR, fmt.Sprintf("Unknown %s type", kind), err.Error(), address, "", block.LabelRanges[0].Ptr())
+		return
+	}
+	if schema.Deprecated {
+		v.add(SEVERITY_WARNING, fmt.Sprintf("Deprecated %s type", kind), fmt.Sprintf("%s is deprecated. Refer to the provider documentation for its replacement.", block.Labels[0]), address, "", block.LabelRanges[0].Ptr())
+	}
+
+	v.body(address, "", block.Body, schema, block.DefRange(), metaArguments[kind], metaBlocks[kind])
+}
+
+// schema looks a type up once, however many blocks use it
+func (v *validator) schema(kind, typeName string) (*tfjson.SchemaBlock, error) {
+	key := kind + "." + typeName
+	if err, ok := v.errors[key]; ok {
+		return nil, err
+	}
+	if schema, ok := v.schemas[key]; ok {
+		return schema, nil
+	}
+
+	schema, err := v.schemaOf(kind, typeName)
+	if err != nil {
+		v.errors[key] = err
+		return nil, err
+	}
+	v.schemas[key] = schema
+	return schema, nil
+}
+
+func (v *validator) body(address, path string, body *hclsyntax.Body, schema *tfjson.SchemaBlock, defRange hcl.Range, metaArgs, metaBlockTypes []string) {
+	for name, attr := range body.Attributes {
+		if utils.IsInList(name, metaArgs) {
+			continue
+		}
+
+		schemaAttr, ok := schema.Attributes[name]
+		if !ok {
+			detail := fmt.Sprintf("An argument named %q is not expected here.", name)
+			if _, isBlock := schema.NestedBlocks[name]; isBlock {
+				detail = fmt.Sprintf("%q is a block, not an argument; write it as %s { ... }.", name, name)
+			} else {
+				detail += suggestion(name, sortedKeys(schema.Attributes))
+			}
+			v.add(SEVERITY_ERROR, "Unsupported argument", detail, address, joinPath(path, name), attr.NameRange.Ptr())
+			continue
+		}
+
+		v.attribute(address, joinPath(path, name), attr, schemaAttr)
+	}
+
+	for _, name := range sortedKeys(schema.Attributes) {
+		if _, ok := body.Attributes[name]; !ok && schema.Attributes[name].Required {
+			v.add(SEVERITY_ERROR, "Missing required argument", fmt.Sprintf("The argument %q is required, but no definition was found.", name), address, joinPath(path, name), defRange.Ptr())
+		}
+	}
+
+	counts := map[string]int{}
+	dynamic := map[string]bool{}
+	last := map[string]hcl.Range{}
+	for _, block := range body.Blocks {
+		if utils.IsInList(block.Type, metaBlockTypes) {
+			continue
+		}
+
+		name, content := block.Type, block.Body
+		if block.Type == "dynamic" {
+			if len(block.Labels) != 1 {
+				v.add(SEVERITY_ERROR, "Wrong number of labels", "A dynamic block has 1 label: the name of the block it generates.", address, path, block.DefRange().Ptr())
+				continue
+			}
+			name, content = block.Labels[0], nil
+			for _, inner := range block.Body.Blocks {
+				if inner.Type == "content" {
+					content = inner.Body
+				}
+			}
+			dynamic[name] = true
+		}
+
+		blockType, ok := schema.NestedBlocks[name]
+		if !ok {
+			detail := fmt.Sprintf("Blocks of type %q are not expected here.", name)
+			if _, isAttr := schema.Attributes[name]; isAttr {
+				detail = fmt.Sprintf("%q is an argument, not a block; write it as %s = ...", name, name)
+			} else {
+				detail += suggestion(name, sortedKeys(schema.NestedBlocks))
+			}
+			v.add(SEVERITY_ERROR, "Unsupported block type", detail, address, joinPath(path, name), block.TypeRange.Ptr())
+			continue
+		}
+
+		if block.Type != "dynamic" {
+			counts[name]++
+			last[name] = block.DefRange()
+			if len(block.Labels) > 0 {
+				v.add(SEVERITY_ERROR, "Extraneous label", fmt.Sprintf("A %s block has no labels.", name), address, joinPath(path, name), block.LabelRanges[0].Ptr())
+			}
+		}
+
+		if blockType.Block == nil || content == nil {
+			continue
+		}
+		if blockType.Block.Deprecated {
+			v.add(SEVERITY_WARNING, "Block is deprecated", fmt.Sprintf("%q is deprecated. Refer to the provider documentation for details.", joinPath(path, name)), address, joinPath(path, name), block.TypeRange.Ptr())
+		}
+		v.body(address, joinPath(path, name), content, blockType.Block, block.DefRange(), nil, nil)
+	}
+
+	for _, name := range sortedKeys(schema.NestedBlocks) {
+		if dynamic[name] {
+			continue
+		}
+
+		blockType := schema.NestedBlocks[name]
+		maxItems := blockType.MaxItems
+		if blockType.NestingMode == tfjson.SchemaNestingModeSingle || blockType.NestingMode == tfjson.SchemaNestingModeGroup {
+			maxItems = 1
+		}
+
+		count := uint64(counts[name])
+		switch {
+		case count < blockType.MinItems:
+			v.add(SEVERITY_ERROR, fmt.Sprintf("Insufficient %s blocks", name), fmt.Sprintf("At least %d %q blocks are required.", blockType.MinItems, name), address, joinPath(path, name), defRange.Ptr())
+		case maxItems > 0 && count > maxItems:
+			extra := last[name]
+			v.add(SEVERITY_ERROR, fmt.Sprintf("Too many %s blocks", name), fmt.Sprintf("No more than %d %q blocks are allowed.", maxItems, name), address, joinPath(path, name), extra.Ptr())
+		}
+	}
+}
+
+func (v *validator) attribute(address, path string, attr *hclsyntax.Attribute, schemaAttr *tfjson.SchemaAttribute) {
+	if !schemaAttr.Required && !schemaAttr.Optional {
+		v.add(SEVERITY_ERROR, "Value for unconfigurable attribute", fmt.Sprintf("%q is computed by the provider and cannot be set.", attr.Name), address, path, attr.NameRange.Ptr())
+		return
+	}
+	if schemaAttr.Deprecated {
+		v.add(SEVERITY_WARNING, "Argument is deprecated", fmt.Sprintf("%q is deprecated. Refer to the provider documentation for details.", path), address, path, attr.NameRange.Ptr())
+	}
+
+	// Only values known without evaluating references or functions can be checked
+	attrType := attributeType(schemaAttr)
+	if attrType == cty.NilType || attrType == cty.DynamicPseudoType || len(attr.Expr.Variables()) > 0 {
+		return
+	}
+	value, diags := attr.Expr.Value(nil)
+	if diags.HasErrors() || !value.IsWhollyKnown() {
+		return
+	}
+
+	if _, err := convert.Convert(value, attrType); err != nil {
+		detail := fmt.Sprintf("Inappropriate value for attribute %q: %s.", attr.Name, err.Error())
+		v.add(SEVERITY_ERROR, "Incorrect attribute value type", detail, address, path, attr.Expr.Range().Ptr())
+	}
+}
+
+func (v *validator) add(severity, summary, detail, address, path string, subject *hcl.Range) {
+	diag := Diagnostic{Severity: severity, Summary: summary, Detail: detail, Address: address, Path: path}
+	if subject != nil {
+		diag.Line, diag.Column = subject.Start.Line, subject.Start.Column
+	}
+	v.diagnostics = append(v.diagnostics, diag)
+}
+
+// attributeType is the type a value of an attribute converts to. Nested
+// attributes are objects whose optional attributes may be omitted.
+func attributeType(attr *tfjson.SchemaAttribute) cty.Type {
+	nested := attr.AttributeNestedType
+	if nested == nil {
+		return attr.AttributeType
+	}
+
+	attrTypes := map[string]cty.Type{}
+	optional := []string{}
+	for name, nestedAttr := range nested.Attributes {
+		attrTypes[name] = attributeType(nestedAttr)
+		if !nestedAttr.Required {
+			optional = append(optional, name)
+		}
+	}
+	object := cty.ObjectWithOptionalAttrs(attrTypes, optional)
+
+	switch nested.NestingMode {
+	case tfjson.SchemaNestingModeList:
+		return cty.List(object)
+	case tfjson.SchemaNestingModeSet:
+		return cty.Set(object)
+	case tfjson.SchemaNestingModeMap:
+		return cty.Map(object)
+	}
+	return object
+}
+
+func suggestion(name string, candidates []string) string {
+	if closest, ok := utils.Closest(name, candidates); ok {
+		return fmt.Sprintf(" Did you mean %q?", closest)
+	}
+	return ""
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,204 @@
+package providerschema
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Yunsang-Jeong/terraform-mcp-server/pkg/utils/docschema"
+	"github.com/Yunsang-Jeong/terraform-mcp-server/pkg/utils/registry"
+
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+func testSchemaFunc(t *testing.T) SchemaFunc {
+	store := newTestStore(t)
+	aws := registry.ProviderAddress{Host: "registry.terraform.io", Namespace: "hashicorp", Name: "aws"}
+
+	return func(kind, typeName string) (*tfjson.SchemaBlock, error) {
+		if block, ok := store.Lookup(aws, kind, typeName); ok {
+			return block, nil
+		}
+		return nil, fmt.Errorf("%s not found", typeName)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		summary string
+		path    string
+		line    int
+	}{
+		{
+			name:    "unknown argument",
+			src:     "resource \"aws_s3_bucket\" \"b\" {\n  buckt = \"x\"\n}\n",
+			summary: "Unsupported argument",
+			path:    "buckt",
+			line:    2,
+		},
+		{
+			name:    "missing required argument",
+			src:     "resource \"aws_s3_bucket\" \"b\" {\n  lifecycle_rule {\n  }\n}\n",
+			summary: "Missing required argument",
+			path:    "lifecycle_rule.enabled",
+			line:    2,
+		},
+		{
+			name:    "wrong nested block",
+			src:     "resource \"aws_s3_bucket\" \"b\" {\n  lifecycle_rule {\n    enabled = true\n    transition {}\n  }\n}\n",
+			summary: "Unsupported block type",
+			path:    "lifecycle_rule.transition",
+			line:    4,
+		},
+		{
+			name:    "type mismatch",
+			src:     "resource \"aws_s3_bucket\" \"b\" {\n  tags = [\"a\"]\n}\n",
+			summary: "Incorrect attribute value type",
+			path:    "tags",
+			line:    2,
+		},
+		{
+			name:    "too many blocks",
+			src:     "resource \"aws_s3_bucket\" \"b\" {\n  lifecycle_rule {\n    enabled = true\n    expiration {}\n    expiration {}\n  }\n}\n",
+			summary: "Too many expiration blocks",
+			path:    "lifecycle_rule.expiration",
+			line:    5,
+		},
+		{
+			name:    "computed attribute",
+			src:     "resource \"aws_db_instance\" \"db\" {\n  master_user_secret = []\n}\n",
+			summary: "Value for unconfigurable attribute",
+			path:    "master_user_secret",
+			line:    2,
+		},
+		{
+			name:    "unknown resource type",
+			src:     "resource \"aws_s3_buckets\" \"b\" {}\n",
+			summary: "Unknown resource type",
+			line:    1,
+		},
+		{
+			name:    "unknown top-level block",
+			src:     "resources \"aws_s3_bucket\" \"b\" {}\n",
+			summary: "Unsupported block type",
+			line:    1,
+		},
+		{
+			name:    "syntax error",
+			src:     "resource \"aws_s3_bucket\" \"b\" {\n  bucket = \n}\n",
+			summary: "Invalid expression",
+			line:    2,
+		},
+	}
+
+	schemaOf := testSchemaFunc(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := Validate([]byte(tt.src), "main.tf", schemaOf)
+
+			if len(diags) != 1 {
+				t.Fatalf("Validate() = %+v, want 1 diagnostic", diags)
+			}
+			if diags[0].Severity != SEVERITY_ERROR || diags[0].Summary != tt.summary || diags[0].Path != tt.path || diags[0].Line != tt.line {
+				t.Errorf("Validate() = %+v, want %s at %s line %d", diags[0], tt.summary, tt.path, tt.line)
+			}
+		})
+	}
+}
+
+func TestValidate_Valid(t *testing.T) {
+	src := `
+provider "aws" {
+  region = "us-east-1"
+  alias  = "east"
+}
+
+variable "days" {
+  type = number
+}
+
+resource "aws_s3_bucket" "b" {
+  count  = 2
+  bucket = "logs-${count.index}"
+  tags   = { Name = "logs", Count = 2 }
+
+  lifecycle_rule {
+    enabled = true
+
+    dynamic "expiration" {
+      for_each = [var.days]
+      content {
+        days = expiration.value
+      }
+    }
+  }
+
+  lifecycle {
+    prevent_destroy = true
+  }
+}
+
+data "aws_ami" "ubuntu" {
+  most_recent = "true"
+}
+`
+
+	if diags := Validate([]byte(src), "main.tf", testSchemaFunc(t)); len(diags) != 0 {
+		t.Errorf("Validate() = %+v, want no diagnostics", diags)
+	}
+}
+
+func TestValidate_Warnings(t *testing.T) {
+	src := "resource \"aws_s3_bucket\" \"b\" {\n  acl = \"private\"\n}\n"
+
+	diags := Validate([]byte(src), "main.tf", testSchemaFunc(t))
+	if len(diags) != 1 || diags[0].Severity != SEVERITY_WARNING || diags[0].Address != "resource.aws_s3_bucket.b" {
+		t.Errorf("Validate() = %+v, want a deprecation warning", diags)
+	}
+}
+
+func TestFromDoc(t *testing.T) {
+	schema := docschema.Schema{
+		Arguments: []docschema.Argument{
+			{Name: "name", Required: true, Type: "string"},
+			{Name: "tags", Type: "map"},
+			{Name: "site_config", Type: "block"},
+		},
+		Blocks: []docschema.Block{
+			{Name: "site_config", Arguments: []docschema.Argument{{Name: "always_on", Type: "bool"}, {Name: "cors", Type: "block"}}},
+			{Name: "cors", Arguments: []docschema.Argument{{Name: "allowed_origins", Required: true, Type: "list"}}},
+			{Name: "identity", Arguments: []docschema.Argument{{Name: "type", Required: true}}},
+		},
+		Timeouts: []docschema.Timeout{{Operation: "create"}},
+	}
+
+	block := FromDoc(schema)
+	schemaOf := func(kind, typeName string) (*tfjson.SchemaBlock, error) { return block, nil }
+
+	valid := `
+resource "azurerm_linux_web_app" "app" {
+  name = "app"
+  site_config {
+    always_on = true
+    cors {
+      allowed_origins = ["*"]
+    }
+  }
+  identity {
+    type = "SystemAssigned"
+  }
+  timeouts {
+    create = "30m"
+  }
+}
+`
+	if diags := Validate([]byte(valid), "main.tf", schemaOf); len(diags) != 0 {
+		t.Errorf("Validate() = %+v, want no diagnostics", diags)
+	}
+
+	invalid := "resource \"azurerm_linux_web_app\" \"app\" {\n  name = \"app\"\n  cors {}\n}\n"
+	if diags := Validate([]byte(invalid), "main.tf", schemaOf); len(diags) != 1 || diags[0].Path != "cors" {
+		t.Errorf("Validate() = %+v, want cors to be unsupported at the top level", diags)
+	}
+}
@@ -0,0 +1,101 @@
+package registry
+
+import (
+	"fmt"
+	"strings"
+)
+
+const DEFAULT_PROVIDER_NAMESPACE = "hashicorp"
+
+// ProviderAddress identifies a provider as [hostname/]namespace/type
+type ProviderAddress struct {
+	Host      string
+	Namespace string
+	Name      string
+}
+
+// ParseProviderAddress parses a provider source address such as "aws",
+// "hashicorp/aws" or "app.terraform.io/acme/aws". Missing parts are filled from
+// the default registry host and namespace.
+func ParseProviderAddress(source, defaultNamespace string) (ProviderAddress, error) {
+	if defaultNamespace == "" {
+		defaultNamespace = DEFAULT_PROVIDER_NAMESPACE
+	}
+
+	addr := ProviderAddress{
+		Host:      DefaultHost(),
+		Namespace: defaultNamespace,
+	}
+
+	parts := strings.Split(strings.TrimSpace(source), "/")
+	for _, part := range parts {
+		if part == "" {
+			return addr, fmt.Errorf("invalid provider address: %q", source)
+		}
+	}
+
+	switch len(parts) {
+	case 1:
+		addr.Name = parts[0]
+	case 2:
+		addr.Namespace, addr.Name = parts[0], parts[1]
+	case 3:
+		addr.Host, addr.Namespace, addr.Name = normalizeHost(parts[0]), parts[1], parts[2]
+	default:
+		return addr, fmt.Errorf("invalid provider address: %q", source)
+	}
+
+	return addr, nil
+}
+
+func (a ProviderAddress) String() string {
+	return fmt.Sprintf("%s/%s/%s", a.Host, a.Namespace, a.Name)
+}
+
+// Client returns the registry client for the provider's host
+func (a ProviderAddress) Client() (*Client, error) {
+	return ClientFor(a.Host)
+}
+
+// ModuleAddress identifies a registry module as [hostname/]namespace/name/system
+type ModuleAddress struct {
+	Host      string
+	Namespace string
+	Name      string
+	// System is the target system of the module, usually the main provider it uses
+	System string
+}
+
+// ParseModuleAddress parses a registry module address such as
+// "terraform-aws-modules/vpc/aws" or "app.terraform.io/acme/network/azurerm".
+// A missing hostname is filled with the default registry host.
+func ParseModuleAddress(source string) (ModuleAddress, error) {
+	addr := ModuleAddress{Host: DefaultHost()}
+
+	parts := strings.Split(strings.TrimSpace(source), "/")
+	for _, part := range parts {
+		if part == "" {
+			return addr, fmt.Errorf("invalid module address: %q", source)
+		}
+	}
+
+	switch len(parts) {
+	case 3:
+		addr.Namespace, addr.Name, addr.System = parts[0], parts[1], parts[2]
+	case 4:
+		addr.Host, addr.Namespace, addr.Name, addr.System = normalizeHost(parts[0]), parts[1], parts[2], parts[3]
+	default:
+		return addr, fmt.Errorf("invalid module address: %q; expected namespace/name/system", source)
+	}
+
+	return addr, nil
+}
+
+func (a ModuleAddress) String() string {
+	return fmt.Sprintf("%s/%s/%s/%s", a.Host, a.Namespace, a.Name, a.System)
+}
+
+// Client returns the registry client for the module's host
+func (a ModuleAddress) Client() (*Client, error) {
+	return ClientFor(a.Host)
+}
@@ -0,0 +1,167 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DEFAULT_REGISTRY_HOST = "registry.terraform.io"
+	DEFAULT_USER_AGENT    = "terraform-mcp/0.1 (+public-registry)"
+)
+
+// defaultTransport is shared by clients created outside of Configure so they reuse connections
+var defaultTransport = NewTransport(DefaultTransportConfig())
+
+// Client talks to a single Terraform registry host
+type Client struct {
+	BaseURL    *url.URL
+	HTTPClient *http.Client
+	UserAgent  string
+	Token      string
+	Cache      *DiskCache
+	Memory     *MemoryCache
+
+	servicesMu sync.Mutex
+	services   map[string]*url.URL
+}
+
+// NewClient creates a registry client for the given base URL (e.g. https://registry.terraform.io)
+func NewClient(baseURL string) (*Client, error) {
+	if !strings.Contains(baseURL, "://") {
+		baseURL = "https://" + baseURL
+	}
+
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing registry URL: %w", err)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("registry URL must have a host: %s", baseURL)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/")
+
+	return &Client{
+		BaseURL:    u,
+		HTTPClient: &http.Client{Transport: defaultTransport, Timeout: DEFAULT_TIMEOUT},
+		UserAgent:  DEFAULT_USER_AGENT,
+	}, nil
+}
+
+// Host returns the hostname the client is bound to
+func (c *Client) Host() string {
+	return c.BaseURL.Host
+}
+
+// GetSomething performs a GET request against the registry and returns the raw body
+func (c *Client) GetSomething(ctx context.Context, path string, query map[string]string) ([]byte, error) {
+	u := *c.BaseURL
+
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	u.Path = c.BaseURL.Path + path
+
+	return c.getURL(ctx, &u, query)
+}
+
+func (c *Client) getURL(ctx context.Context, u *url.URL, query map[string]string) ([]byte, error) {
+	q := u.Query()
+	for k, v := range query {
+		q.Set(k, v)
+	}
+	u.RawQuery = q.Encode()
+
+	if c.Memory != nil {
+		return c.Memory.Do(ctx, cacheKey(u, c.Token), func(ctx context.Context) ([]byte, error) {
+			return c.fetch(ctx, u)
+		})
+	}
+
+	return c.fetch(ctx, u)
+}
+
+// fetch downloads u, going through the disk cache when one is configured
+func (c *Client) fetch(ctx context.Context, u *url.URL) ([]byte, error) {
+	var entry *diskCacheEntry
+	if c.Cache != nil {
+		if cached, ok := c.Cache.get(cacheKey(u, c.Token)); ok {
+			if cached.fresh(time.Now()) {
+				return cached.Body, nil
+			}
+			entry = cached
+		}
+	}
+
+	req, err := c.newRequest(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	if entry != nil {
+		entry.setConditionalHeaders(req)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode == http.StatusNotModified && entry != nil {
+		if entry.update(resp.Header, time.Now()) {
+			c.storeCache(u, entry)
+		}
+		return entry.Body, nil
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, &StatusError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	if c.Cache != nil {
+		fresh := &diskCacheEntry{URL: u.String(), Immutable: isImmutable(u), Body: body}
+		if fresh.update(resp.Header, time.Now()) && (fresh.Immutable || fresh.revalidatable() || fresh.fresh(time.Now())) {
+			c.storeCache(u, fresh)
+		}
+	}
+
+	return body, nil
+}
+
+func (c *Client) newRequest(ctx context.Context, u *url.URL) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("User-Agent", c.UserAgent)
+	req.Header.Set("Accept", "application/json")
+	// Credentials belong to the registry host and are never sent to other hosts
+	if c.Token != "" && u.Host == c.BaseURL.Host {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	return req, nil
+}
+
+func (c *Client) storeCache(u *url.URL, entry *diskCacheEntry) {
+	// A cache write failure only costs a future re-download
+	_ = c.Cache.put(cacheKey(u, c.Token), entry)
+}
+
+// StatusError is returned when the registry answers with a non-2xx status
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("registry error: status=%d body=%s", e.StatusCode, e.Body)
+}
@@ -0,0 +1,113 @@
+package registry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestClientGetSomething(t *testing.T) {
+	var gotPath, gotQuery, gotUA, gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotQuery = r.URL.RawQuery
+		gotUA = r.Header.Get("User-Agent")
+		gotAuth = r.Header.Get("Authorization")
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL + "/prefix/")
+	if err != nil {
+		t.Fatalf("NewClient() unexpected error: %v", err)
+	}
+	c.UserAgent = "test-agent"
+	c.Token = "secret"
+
+	body, err := c.GetSomething(context.Background(), "v1/providers/hashicorp/aws", map[string]string{"include": "x"})
+	if err != nil {
+		t.Fatalf("GetSomething() unexpected error: %v", err)
+	}
+
+	if string(body) != `{"ok":true}` {
+		t.Errorf("body = %s", body)
+	}
+	if gotPath != "/prefix/v1/providers/hashicorp/aws" {
+		t.Errorf("path = %s", gotPath)
+	}
+	if gotQuery != "include=x" {
+		t.Errorf("query = %s", gotQuery)
+	}
+	if gotUA != "test-agent" {
+		t.Errorf("user agent = %s", gotUA)
+	}
+	if gotAuth != "Bearer secret" {
+		t.Errorf("authorization = %s", gotAuth)
+	}
+}
+
+func TestClientGetSomething_ErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL)
+	if err != nil {
+		t.Fatalf("NewClient() unexpected error: %v", err)
+	}
+
+	if _, err := c.GetSomething(context.Background(), "/missing", nil); err == nil {
+		t.Error("GetSomething() expected error for 404")
+	}
+}
+
+func TestClientFor(t *testing.T) {
+	t.Cleanup(func() { Configure(Config{}) })
+
+	err := Configure(Config{
+		DefaultHost: "Registry.Example.com",
+		Hosts:       map[string]string{"registry.example.com": "http://127.0.0.1:9999/base"},
+		UserAgent:   "custom",
+		Tokens:      map[string]string{"REGISTRY.example.com": "tok"},
+	})
+	if err != nil {
+		t.Fatalf("Configure() unexpected error: %v", err)
+	}
+
+	c, err := Default()
+	if err != nil {
+		t.Fatalf("Default() unexpected error: %v", err)
+	}
+	if c.BaseURL.String() != "http://127.0.0.1:9999/base" {
+		t.Errorf("base URL = %s", c.BaseURL)
+	}
+	if c.UserAgent != "custom" || c.Token != "tok" {
+		t.Errorf("user agent = %s, token = %s", c.UserAgent, c.Token)
+	}
+
+	other, err := ClientFor("app.terraform.io")
+	if err != nil {
+		t.Fatalf("ClientFor() unexpected error: %v", err)
+	}
+	if other.BaseURL.String() != "https://app.terraform.io" || other.Token != "" {
+		t.Errorf("unexpected client for app.terraform.io: %s %s", other.BaseURL, other.Token)
+	}
+
+	again, _ := ClientFor("APP.terraform.io")
+	if again != other {
+		t.Error("ClientFor() should reuse the client for a host")
+	}
+}
+
+func mustParseURL(t *testing.T, raw string) *url.URL {
+	t.Helper()
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatalf("url.Parse(%s) unexpected error: %v", raw, err)
+	}
+	return u
+}
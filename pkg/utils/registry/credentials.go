@@ -0,0 +1,191 @@
+package registry
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const TOKEN_ENV_PREFIX = "TF_TOKEN_"
+
+// CredentialsSources tells LoadCredentials where to look for Terraform CLI credentials
+type CredentialsSources struct {
+	// CredentialsFile is the credentials.tfrc.json written by `terraform login`
+	CredentialsFile string
+	// CLIConfigFile is a Terraform CLI configuration file with `credentials` blocks
+	CLIConfigFile string
+}
+
+// DefaultCredentialsSources returns the locations Terraform CLI itself uses
+func DefaultCredentialsSources() CredentialsSources {
+	sources := CredentialsSources{
+		CLIConfigFile: os.Getenv("TF_CLI_CONFIG_FILE"),
+	}
+
+	configDir := ""
+	if runtime.GOOS == "windows" {
+		configDir = filepath.Join(os.Getenv("APPDATA"), "terraform.d")
+		if sources.CLIConfigFile == "" {
+			sources.CLIConfigFile = filepath.Join(os.Getenv("APPDATA"), "terraform.rc")
+		}
+	} else if home, err := os.UserHomeDir(); err == nil {
+		configDir = filepath.Join(home, ".terraform.d")
+		if sources.CLIConfigFile == "" {
+			sources.CLIConfigFile = filepath.Join(home, ".terraformrc")
+		}
+	}
+
+	if configDir != "" {
+		sources.CredentialsFile = filepath.Join(configDir, "credentials.tfrc.json")
+	}
+
+	return sources
+}
+
+// LoadCredentials collects registry tokens keyed by host. Later sources take
+// precedence: credentials.tfrc.json, then CLI config `credentials` blocks, then
+// TF_TOKEN_<host> environment variables. Missing files are ignored.
+func LoadCredentials(sources CredentialsSources) (map[string]string, error) {
+	tokens := map[string]string{}
+
+	if sources.CredentialsFile != "" {
+		fileTokens, err := loadCredentialsFile(sources.CredentialsFile)
+		if err != nil {
+			return nil, err
+		}
+		for host, token := range fileTokens {
+			tokens[host] = token
+		}
+	}
+
+	if sources.CLIConfigFile != "" {
+		configTokens, err := loadCLIConfigCredentials(sources.CLIConfigFile)
+		if err != nil {
+			return nil, err
+		}
+		for host, token := range configTokens {
+			tokens[host] = token
+		}
+	}
+
+	for host, token := range loadEnvCredentials(os.Environ()) {
+		tokens[host] = token
+	}
+
+	return tokens, nil
+}
+
+func loadCredentialsFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credentials file %s: %w", path, err)
+	}
+
+	var file struct {
+		Credentials map[string]struct {
+			Token string `json:"token"`
+		} `json:"credentials"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse credentials file %s: %w", path, err)
+	}
+
+	tokens := map[string]string{}
+	for host, cred := range file.Credentials {
+		if cred.Token != "" {
+			tokens[normalizeHost(host)] = cred.Token
+		}
+	}
+
+	return tokens, nil
+}
+
+func loadCLIConfigCredentials(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CLI config file %s: %w", path, err)
+	}
+
+	parser := hclparse.NewParser()
+
+	var file *hcl.File
+	var diags hcl.Diagnostics
+	if strings.HasSuffix(path, ".json") {
+		file, diags = parser.ParseJSON(data, path)
+	} else {
+		file, diags = parser.ParseHCL(data, path)
+	}
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("failed to parse CLI config file %s: %w", path, errors.Join(diags.Errs()...))
+	}
+
+	content, _, diags := file.Body.PartialContent(&hcl.BodySchema{
+		Blocks: []hcl.BlockHeaderSchema{
+			{Type: "credentials", LabelNames: []string{"host"}},
+		},
+	})
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("failed to read credentials in %s: %w", path, errors.Join(diags.Errs()...))
+	}
+
+	tokens := map[string]string{}
+	for _, block := range content.Blocks {
+		attrs, diags := block.Body.JustAttributes()
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("invalid credentials block for %s: %w", block.Labels[0], errors.Join(diags.Errs()...))
+		}
+
+		attr, ok := attrs["token"]
+		if !ok {
+			continue
+		}
+
+		value, diags := attr.Expr.Value(nil)
+		if diags.HasErrors() || !value.Type().Equals(cty.String) || value.IsNull() {
+			return nil, fmt.Errorf("credentials token for %s must be a string", block.Labels[0])
+		}
+		tokens[normalizeHost(block.Labels[0])] = value.AsString()
+	}
+
+	return tokens, nil
+}
+
+// loadEnvCredentials reads TF_TOKEN_<host> variables, where dots in the host are
+// written as underscores and hyphens as double underscores
+func loadEnvCredentials(environ []string) map[string]string {
+	tokens := map[string]string{}
+
+	for _, kv := range environ {
+		name, token, ok := strings.Cut(kv, "=")
+		if !ok || token == "" || !strings.HasPrefix(name, TOKEN_ENV_PREFIX) {
+			continue
+		}
+
+		encoded := strings.TrimPrefix(name, TOKEN_ENV_PREFIX)
+		if encoded == "" {
+			continue
+		}
+
+		host := strings.ReplaceAll(encoded, "__", "\x00")
+		host = strings.ReplaceAll(host, "_", ".")
+		host = strings.ReplaceAll(host, "\x00", "-")
+
+		tokens[normalizeHost(host)] = token
+	}
+
+	return tokens
+}
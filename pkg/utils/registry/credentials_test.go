@@ -0,0 +1,78 @@
+package registry
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadCredentials(t *testing.T) {
+	dir := t.TempDir()
+
+	credentialsFile := filepath.Join(dir, "credentials.tfrc.json")
+	os.WriteFile(credentialsFile, []byte(`{
+  "credentials": {
+    "app.terraform.io": {"token": "from-file"},
+    "tf.internal.example": {"token": "internal-file"}
+  }
+}`), 0o600)
+
+	cliConfigFile := filepath.Join(dir, ".terraformrc")
+	os.WriteFile(cliConfigFile, []byte(`
+plugin_cache_dir = "/tmp/plugins"
+
+credentials "tf.internal.example" {
+  token = "internal-config"
+}
+
+credentials "gitlab.example.com" {
+  token = "gitlab-config"
+}
+`), 0o600)
+
+	t.Setenv("TF_TOKEN_app_terraform_io", "from-env")
+	t.Setenv("TF_TOKEN_my__registry_example_com", "hyphen-env")
+
+	tokens, err := LoadCredentials(CredentialsSources{
+		CredentialsFile: credentialsFile,
+		CLIConfigFile:   cliConfigFile,
+	})
+	if err != nil {
+		t.Fatalf("LoadCredentials() unexpected error: %v", err)
+	}
+
+	expected := map[string]string{
+		"app.terraform.io":        "from-env",
+		"tf.internal.example":     "internal-config",
+		"gitlab.example.com":      "gitlab-config",
+		"my-registry.example.com": "hyphen-env",
+	}
+	for host, token := range expected {
+		if tokens[host] != token {
+			t.Errorf("token for %s = %q, want %q", host, tokens[host], token)
+		}
+	}
+}
+
+func TestLoadCredentials_MissingFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := LoadCredentials(CredentialsSources{
+		CredentialsFile: filepath.Join(dir, "missing.json"),
+		CLIConfigFile:   filepath.Join(dir, "missing.rc"),
+	})
+	if err != nil {
+		t.Fatalf("LoadCredentials() unexpected error: %v", err)
+	}
+}
+
+func TestLoadCredentials_InvalidConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	cliConfigFile := filepath.Join(dir, ".terraformrc")
+	os.WriteFile(cliConfigFile, []byte(`credentials "x" { token = 42 }`), 0o600)
+
+	if _, err := LoadCredentials(CredentialsSources{CLIConfigFile: cliConfigFile}); err == nil {
+		t.Error("LoadCredentials() expected error for non-string token")
+	}
+}
@@ -0,0 +1,130 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	DISCOVERY_PATH = "/.well-known/terraform.json"
+
+	SERVICE_PROVIDERS_V1 = "providers.v1"
+	SERVICE_MODULES_V1   = "modules.v1"
+)
+
+// defaultServices are used when a host does not publish a discovery document
+var defaultServices = map[string]string{
+	SERVICE_PROVIDERS_V1: "/v1/providers/",
+	SERVICE_MODULES_V1:   "/v1/modules/",
+}
+
+// Discover resolves the services published by the registry host through
+// Terraform's remote service discovery protocol. Results are cached on the client.
+func (c *Client) Discover(ctx context.Context) (map[string]*url.URL, error) {
+	c.servicesMu.Lock()
+	defer c.servicesMu.Unlock()
+
+	if c.services != nil {
+		return c.services, nil
+	}
+
+	u := c.discoveryURL()
+
+	raw := map[string]json.RawMessage{}
+
+	data, err := c.getURL(ctx, &u, nil)
+	var statusErr *StatusError
+	switch {
+	case errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound:
+		// Hosts without a discovery document (e.g. a local stand-in) use the conventional paths
+		for id, path := range defaultServices {
+			raw[id], _ = json.Marshal(path)
+		}
+	case err != nil:
+		return nil, fmt.Errorf("service discovery for %s failed: %w", c.Host(), err)
+	default:
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("invalid discovery document from %s: %w", c.Host(), err)
+		}
+	}
+
+	services := map[string]*url.URL{}
+	for id, value := range raw {
+		var ref string
+		if err := json.Unmarshal(value, &ref); err != nil {
+			// Some services (e.g. login.v1) are described by objects, not URLs
+			continue
+		}
+
+		serviceURL, err := c.resolveService(ref)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s endpoint from %s: %w", id, c.Host(), err)
+		}
+		services[id] = serviceURL
+	}
+
+	c.services = services
+
+	return services, nil
+}
+
+// ServiceURL returns the base URL of a discovered service
+func (c *Client) ServiceURL(ctx context.Context, id string) (*url.URL, error) {
+	services, err := c.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	serviceURL, ok := services[id]
+	if !ok {
+		return nil, fmt.Errorf("host %s does not provide %s", c.Host(), id)
+	}
+
+	return serviceURL, nil
+}
+
+// GetService performs a GET request against a path relative to a discovered service
+func (c *Client) GetService(ctx context.Context, id, path string, query map[string]string) ([]byte, error) {
+	serviceURL, err := c.ServiceURL(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	u := *serviceURL
+	u.Path = strings.TrimSuffix(serviceURL.Path, "/") + "/" + strings.TrimPrefix(path, "/")
+
+	return c.getURL(ctx, &u, query)
+}
+
+func (c *Client) resolveService(ref string) (*url.URL, error) {
+	refURL, err := url.Parse(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	// Host-relative endpoints keep the configured base path prefix
+	if refURL.Host == "" && strings.HasPrefix(refURL.Path, "/") {
+		refURL.Path = c.BaseURL.Path + refURL.Path
+	}
+
+	// Relative endpoints are resolved against the discovery document URL
+	discoveryURL := c.discoveryURL()
+	resolved := discoveryURL.ResolveReference(refURL)
+	if !strings.HasSuffix(resolved.Path, "/") {
+		resolved.Path += "/"
+	}
+
+	return resolved, nil
+}
+
+func (c *Client) discoveryURL() url.URL {
+	u := *c.BaseURL
+	u.Path = c.BaseURL.Path + DISCOVERY_PATH
+	u.RawQuery = ""
+	return u
+}
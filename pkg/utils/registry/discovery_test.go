@@ -0,0 +1,110 @@
+package registry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestDiscover(t *testing.T) {
+	var discoveries int32
+	mux := http.NewServeMux()
+	mux.HandleFunc(DISCOVERY_PATH, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&discoveries, 1)
+		w.Write([]byte(`{"providers.v1":"/api/registry/v1/providers/","modules.v1":"https://modules.example.com/v1/modules","login.v1":{"client":"terraform-cli"}}`))
+	})
+	mux.HandleFunc("/api/registry/v1/providers/acme/widget", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"namespace":"acme","name":"widget","version":"1.2.3"}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL)
+	if err != nil {
+		t.Fatalf("NewClient() unexpected error: %v", err)
+	}
+
+	provider, err := c.GetProvider(context.Background(), "acme", "widget")
+	if err != nil {
+		t.Fatalf("GetProvider() unexpected error: %v", err)
+	}
+	if provider.Version != "1.2.3" {
+		t.Errorf("version = %s, want 1.2.3", provider.Version)
+	}
+
+	modules, err := c.ServiceURL(context.Background(), SERVICE_MODULES_V1)
+	if err != nil {
+		t.Fatalf("ServiceURL() unexpected error: %v", err)
+	}
+	if modules.String() != "https://modules.example.com/v1/modules/" {
+		t.Errorf("modules.v1 = %s", modules)
+	}
+
+	if _, err := c.ServiceURL(context.Background(), "login.v1"); err == nil {
+		t.Error("ServiceURL() expected error for object-valued service")
+	}
+
+	if _, err := c.GetProviderLatestVersion(context.Background(), "acme", "widget"); err != nil {
+		t.Fatalf("second lookup unexpected error: %v", err)
+	}
+	if n := atomic.LoadInt32(&discoveries); n != 1 {
+		t.Errorf("discovery document fetched %d times, want 1", n)
+	}
+}
+
+func TestDiscover_FallbackWithoutDocument(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/prefix/v1/providers/hashicorp/aws", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"version":"5.0.0"}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL + "/prefix")
+	if err != nil {
+		t.Fatalf("NewClient() unexpected error: %v", err)
+	}
+
+	provider, err := c.GetProvider(context.Background(), "hashicorp", "aws")
+	if err != nil {
+		t.Fatalf("GetProvider() unexpected error: %v", err)
+	}
+	if provider.Version != "5.0.0" {
+		t.Errorf("version = %s, want 5.0.0", provider.Version)
+	}
+}
+
+func TestParseProviderAddress(t *testing.T) {
+	tests := []struct {
+		source  string
+		want    ProviderAddress
+		wantErr bool
+	}{
+		{source: "aws", want: ProviderAddress{Host: DEFAULT_REGISTRY_HOST, Namespace: "hashicorp", Name: "aws"}},
+		{source: "integrations/github", want: ProviderAddress{Host: DEFAULT_REGISTRY_HOST, Namespace: "integrations", Name: "github"}},
+		{source: "app.terraform.io/acme/aws", want: ProviderAddress{Host: "app.terraform.io", Namespace: "acme", Name: "aws"}},
+		{source: "TF.Internal.Example/acme/widget", want: ProviderAddress{Host: "tf.internal.example", Namespace: "acme", Name: "widget"}},
+		{source: "a/b/c/d", wantErr: true},
+		{source: "acme//aws", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			got, err := ParseProviderAddress(tt.source, "")
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseProviderAddress() expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseProviderAddress() unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseProviderAddress() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package registry
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DiskCache stores registry responses on disk so they survive restarts
+type DiskCache struct {
+	Dir string
+}
+
+type diskCacheEntry struct {
+	URL          string    `json:"url"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	Expires      time.Time `json:"expires"`
+	Immutable    bool      `json:"immutable,omitempty"`
+	Body         []byte    `json:"body"`
+}
+
+// DefaultCacheDir returns the registry cache directory under the user cache dir
+func DefaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "terraform-mcp-server", "registry")
+}
+
+// NewDiskCache creates the cache directory if needed
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory %s: %w", dir, err)
+	}
+	return &DiskCache{Dir: dir}, nil
+}
+
+func (d *DiskCache) get(key string) (*diskCacheEntry, bool) {
+	data, err := os.ReadFile(d.path(key))
+	if err != nil {
+		return nil, false
+	}
+
+	var entry diskCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, false
+	}
+
+	return &entry, true
+}
+
+func (d *DiskCache) put(key string, entry *diskCacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	// Write through a temp file so concurrent readers never see a partial entry
+	tmp, err := os.CreateTemp(d.Dir, ".entry-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), d.path(key))
+}
+
+func (d *DiskCache) path(key string) string {
+	return filepath.Join(d.Dir, key+".json")
+}
+
+// cacheKey identifies a response by URL and the credentials used to fetch it
+func cacheKey(u *url.URL, token string) string {
+	h := sha256.New()
+	h.Write([]byte(u.String()))
+	if token != "" {
+		h.Write([]byte{0})
+		h.Write([]byte(token))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// fresh reports whether the entry can be served without contacting the registry
+func (e *diskCacheEntry) fresh(now time.Time) bool {
+	return e.Immutable || now.Before(e.Expires)
+}
+
+// revalidatable reports whether a conditional request can be made for the entry
+func (e *diskCacheEntry) revalidatable() bool {
+	return e.ETag != "" || e.LastModified != ""
+}
+
+func (e *diskCacheEntry) setConditionalHeaders(req *http.Request) {
+	if e.ETag != "" {
+		req.Header.Set("If-None-Match", e.ETag)
+	}
+	if e.LastModified != "" {
+		req.Header.Set("If-Modified-Since", e.LastModified)
+	}
+}
+
+// update refreshes validators and expiry from response headers. It returns
+// false when the response must not be stored.
+func (e *diskCacheEntry) update(header http.Header, now time.Time) bool {
+	if etag := header.Get("ETag"); etag != "" {
+		e.ETag = etag
+	}
+	if lastModified := header.Get("Last-Modified"); lastModified != "" {
+		e.LastModified = lastModified
+	}
+
+	e.Expires = now
+	hasMaxAge := false
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(strings.ToLower(directive)), "=")
+		switch name {
+		case "no-store":
+			return false
+		case "no-cache":
+			e.Expires = now
+			hasMaxAge = true
+		case "max-age":
+			if seconds, err := strconv.Atoi(value); err == nil && !hasMaxAge {
+				e.Expires = now.Add(time.Duration(seconds) * time.Second)
+				hasMaxAge = true
+			}
+		case "immutable":
+			e.Immutable = true
+		}
+	}
+
+	if !hasMaxAge {
+		if expires, err := http.ParseTime(header.Get("Expires")); err == nil {
+			e.Expires = expires
+		}
+	}
+
+	return true
+}
+
+// moduleVersionPath matches the details of a module version, which never change once published
+var moduleVersionPath = regexp.MustCompile(`/modules/[^/]+/[^/]+/[^/]+/v?[0-9]+\.[0-9]+\.[0-9]+[^/]*$`)
+
+// isImmutable reports whether a registry URL always returns the same content.
+// Provider docs are addressed by the ID of a published provider version, which never changes.
+func isImmutable(u *url.URL) bool {
+	path := strings.TrimSuffix(u.Path, "/")
+
+	switch {
+	case strings.Contains(path, "/v2/provider-docs/"):
+		return true
+	case strings.HasSuffix(path, "/v2/provider-docs"):
+		return u.Query().Get("filter[provider-version]") != ""
+	case moduleVersionPath.MatchString(path):
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,129 @@
+package registry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestDiskCache_ETagRevalidation(t *testing.T) {
+	var requests, notModified int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			atomic.AddInt32(&notModified, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"version":"1.0.0"}`))
+	}))
+	defer srv.Close()
+
+	c := newCachedTestClient(t, srv.URL)
+
+	for i := 0; i < 2; i++ {
+		body, err := c.GetSomething(context.Background(), "/v1/providers/hashicorp/aws", nil)
+		if err != nil {
+			t.Fatalf("GetSomething() unexpected error: %v", err)
+		}
+		if string(body) != `{"version":"1.0.0"}` {
+			t.Errorf("body = %s", body)
+		}
+	}
+
+	if requests != 2 || notModified != 1 {
+		t.Errorf("requests = %d, not modified = %d, want 2 and 1", requests, notModified)
+	}
+}
+
+func TestDiskCache_MaxAgeAndImmutable(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.URL.Path == "/v1/fresh" {
+			w.Header().Set("Cache-Control", "public, max-age=600")
+		}
+		w.Write([]byte(r.URL.Path))
+	}))
+	defer srv.Close()
+
+	c := newCachedTestClient(t, srv.URL)
+
+	for _, path := range []string{"/v1/fresh", "/v2/provider-docs/123", "/v1/fresh", "/v2/provider-docs/123"} {
+		if _, err := c.GetSomething(context.Background(), path, nil); err != nil {
+			t.Fatalf("GetSomething(%s) unexpected error: %v", path, err)
+		}
+	}
+
+	if requests != 2 {
+		t.Errorf("requests = %d, want 2", requests)
+	}
+
+	// A new client sharing the directory reads the same entries, as after a restart
+	restarted := newCachedTestClient(t, srv.URL)
+	restarted.Cache = c.Cache
+	if _, err := restarted.GetSomething(context.Background(), "/v2/provider-docs/123", nil); err != nil {
+		t.Fatalf("GetSomething() unexpected error: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("requests after restart = %d, want 2", requests)
+	}
+}
+
+func TestDiskCache_NoStore(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	c := newCachedTestClient(t, srv.URL)
+	c.GetSomething(context.Background(), "/v1/private", nil)
+	c.GetSomething(context.Background(), "/v1/private", nil)
+
+	if requests != 2 {
+		t.Errorf("requests = %d, want 2", requests)
+	}
+}
+
+func TestIsImmutable(t *testing.T) {
+	tests := map[string]bool{
+		"https://registry.terraform.io/v2/provider-docs/8814952":                                           true,
+		"https://registry.terraform.io/v2/provider-docs?filter%5Bprovider-version%5D=1&filter%5Bslug%5D=x": true,
+		"https://registry.terraform.io/v2/provider-docs?filter%5Bslug%5D=x":                                false,
+		"https://registry.terraform.io/v1/providers/hashicorp/aws":                                         false,
+		"https://registry.terraform.io/v1/modules/terraform-aws-modules/vpc/aws/5.8.1":                     true,
+		"https://registry.terraform.io/v1/modules/terraform-aws-modules/vpc/aws/versions":                  false,
+		"https://registry.terraform.io/v1/modules/terraform-aws-modules/vpc/aws":                           false,
+	}
+
+	for raw, want := range tests {
+		u := mustParseURL(t, raw)
+		if got := isImmutable(u); got != want {
+			t.Errorf("isImmutable(%s) = %v, want %v", raw, got, want)
+		}
+	}
+}
+
+func newCachedTestClient(t *testing.T, baseURL string) *Client {
+	t.Helper()
+
+	c, err := NewClient(baseURL)
+	if err != nil {
+		t.Fatalf("NewClient() unexpected error: %v", err)
+	}
+
+	cache, err := NewDiskCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewDiskCache() unexpected error: %v", err)
+	}
+	c.Cache = cache
+
+	return c
+}
@@ -0,0 +1,118 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+const DOCS_PAGE_SIZE = 100
+
+// ProviderDoc is an entry of a provider's documentation index
+type ProviderDoc struct {
+	ID          string `json:"id"`
+	Category    string `json:"category"`
+	Subcategory string `json:"subcategory,omitempty"`
+	Slug        string `json:"slug"`
+	Title       string `json:"title"`
+}
+
+// ListProviderDocs returns the hcl documentation index of a provider version,
+// following pagination. An empty category lists every category.
+func (c *Client) ListProviderDocs(ctx context.Context, versionId, category string) ([]ProviderDoc, error) {
+	docs := []ProviderDoc{}
+
+	page := 1
+	for {
+		query := map[string]string{
+			"filter[provider-version]": versionId,
+			"filter[language]":         "hcl",
+			"page[number]":             strconv.Itoa(page),
+			"page[size]":               strconv.Itoa(DOCS_PAGE_SIZE),
+		}
+		if category != "" {
+			query["filter[category]"] = category
+		}
+
+		data, err := c.GetSomething(ctx, "/v2/provider-docs", query)
+		if err != nil {
+			return nil, err
+		}
+
+		var resp RegistryV2ProviderDocs
+		if err := json.Unmarshal(data, &resp); err != nil {
+			return nil, err
+		}
+
+		for _, doc := range resp.Data {
+			docs = append(docs, ProviderDoc{
+				ID:          doc.ID,
+				Category:    doc.Attributes.Category,
+				Subcategory: subcategoryString(doc.Attributes.Subcategory),
+				Slug:        doc.Attributes.Slug,
+				Title:       doc.Attributes.Title,
+			})
+		}
+
+		next := resp.Meta.Pagination.NextPage
+		if next == nil || len(resp.Data) == 0 {
+			break
+		}
+		if *next <= page {
+			return nil, fmt.Errorf("unexpected pagination from registry: page %d points to %d", page, *next)
+		}
+		page = *next
+	}
+
+	return docs, nil
+}
+
+// LatestProviderDocs returns the hcl documentation index of the latest provider version
+func (c *Client) LatestProviderDocs(ctx context.Context, namespace, name string) (string, []ProviderDoc, error) {
+	provider, err := c.GetProvider(ctx, namespace, name)
+	if err != nil {
+		return "", nil, err
+	}
+
+	docs := []ProviderDoc{}
+	for _, doc := range provider.Docs {
+		if doc.Language != "hcl" {
+			continue
+		}
+		docs = append(docs, ProviderDoc{
+			ID:          doc.ID,
+			Category:    doc.Category,
+			Subcategory: doc.Subcategory,
+			Slug:        doc.Slug,
+			Title:       doc.Title,
+		})
+	}
+
+	return provider.Version, docs, nil
+}
+
+// DocCategories returns the distinct categories present in a documentation index, sorted
+func DocCategories(docs []ProviderDoc) []string {
+	seen := map[string]bool{}
+	categories := []string{}
+	for _, doc := range docs {
+		if doc.Category == "" || seen[doc.Category] {
+			continue
+		}
+		seen[doc.Category] = true
+		categories = append(categories, doc.Category)
+	}
+	sort.Strings(categories)
+
+	return categories
+}
+
+// subcategoryString converts the v2 subcategory, which is null when unset
+func subcategoryString(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return ""
+}
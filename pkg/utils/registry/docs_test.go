@@ -0,0 +1,71 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestListProviderDocs_Pagination(t *testing.T) {
+	var pages []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("filter[provider-version]") != "42" || q.Get("filter[category]") != "resources" {
+			t.Errorf("unexpected filters: %s", r.URL.RawQuery)
+		}
+
+		page := q.Get("page[number]")
+		pages = append(pages, page)
+
+		switch page {
+		case "1":
+			fmt.Fprint(w, `{"data":[
+				{"id":"1","attributes":{"category":"resources","slug":"s3_bucket","title":"aws_s3_bucket","subcategory":"S3 (Simple Storage)"}},
+				{"id":"2","attributes":{"category":"resources","slug":"instance","title":"aws_instance","subcategory":null}}
+			],"meta":{"pagination":{"current-page":1,"next-page":2,"total-pages":2}}}`)
+		case "2":
+			fmt.Fprint(w, `{"data":[
+				{"id":"3","attributes":{"category":"resources","slug":"vpc","title":"aws_vpc","subcategory":"VPC (Virtual Private Cloud)"}}
+			],"meta":{"pagination":{"current-page":2,"next-page":null,"total-pages":2}}}`)
+		default:
+			t.Errorf("unexpected page %s", page)
+		}
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL)
+	if err != nil {
+		t.Fatalf("NewClient() unexpected error: %v", err)
+	}
+
+	docs, err := c.ListProviderDocs(context.Background(), "42", "resources")
+	if err != nil {
+		t.Fatalf("ListProviderDocs() unexpected error: %v", err)
+	}
+
+	if len(docs) != 3 || len(pages) != 2 {
+		t.Fatalf("got %d docs from %d pages, want 3 from 2", len(docs), len(pages))
+	}
+	if docs[0].Subcategory != "S3 (Simple Storage)" || docs[1].Subcategory != "" || docs[2].Slug != "vpc" {
+		t.Errorf("unexpected docs: %+v", docs)
+	}
+}
+
+func TestDocCategories(t *testing.T) {
+	docs := []ProviderDoc{
+		{Category: "resources"},
+		{Category: "guides"},
+		{Category: "resources"},
+		{Category: "functions"},
+		{},
+	}
+
+	got := DocCategories(docs)
+	want := []string{"functions", "guides", "resources"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DocCategories() = %v, want %v", got, want)
+	}
+}
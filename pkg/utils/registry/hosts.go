@@ -0,0 +1,162 @@
+package registry
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Config describes how registry clients are created for each host
+type Config struct {
+	// DefaultHost is the registry used when a tool does not name one
+	DefaultHost string
+	// Hosts overrides the base URL used for a registry host (e.g. a local stand-in)
+	Hosts map[string]string
+	// UserAgent sent with every request
+	UserAgent string
+	// Tokens holds bearer tokens keyed by registry host
+	Tokens map[string]string
+	// CacheDir enables the on-disk response cache when set
+	CacheDir string
+	// MemoryCacheEntries, MemoryCacheBytes and MemoryCacheTTL bound the in-process
+	// response cache. It is disabled when MemoryCacheEntries is zero.
+	MemoryCacheEntries int
+	MemoryCacheBytes   int64
+	MemoryCacheTTL     time.Duration
+	// Transport controls retries and rate limiting shared by every client
+	Transport TransportConfig
+	// Timeout bounds a whole registry request, including retries
+	Timeout time.Duration
+}
+
+var (
+	clientsMu sync.RWMutex
+	clients   = map[string]*Client{}
+	config    = Config{DefaultHost: DEFAULT_REGISTRY_HOST}
+	diskCache *DiskCache
+	memCache  *MemoryCache
+	transport http.RoundTripper
+)
+
+// Configure replaces the registry configuration and drops every cached client
+func Configure(cfg Config) error {
+	if cfg.DefaultHost == "" {
+		cfg.DefaultHost = DEFAULT_REGISTRY_HOST
+	}
+	cfg.DefaultHost = normalizeHost(cfg.DefaultHost)
+
+	hosts := map[string]string{}
+	for host, baseURL := range cfg.Hosts {
+		if _, err := NewClient(baseURL); err != nil {
+			return fmt.Errorf("invalid registry URL for %s: %w", host, err)
+		}
+		hosts[normalizeHost(host)] = baseURL
+	}
+	cfg.Hosts = hosts
+
+	tokens := map[string]string{}
+	for host, token := range cfg.Tokens {
+		tokens[normalizeHost(host)] = token
+	}
+	cfg.Tokens = tokens
+
+	var cache *DiskCache
+	if cfg.CacheDir != "" {
+		var err error
+		if cache, err = NewDiskCache(cfg.CacheDir); err != nil {
+			return err
+		}
+	}
+
+	var memory *MemoryCache
+	if cfg.MemoryCacheEntries > 0 {
+		memory = NewMemoryCache(cfg.MemoryCacheEntries, cfg.MemoryCacheBytes, cfg.MemoryCacheTTL)
+	}
+
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DEFAULT_TIMEOUT
+	}
+
+	clientsMu.Lock()
+	defer clientsMu.Unlock()
+
+	config = cfg
+	clients = map[string]*Client{}
+	diskCache = cache
+	memCache = memory
+	transport = NewTransport(cfg.Transport)
+
+	return nil
+}
+
+// SetClient registers the client used for the given registry host
+func SetClient(host string, c *Client) {
+	clientsMu.Lock()
+	defer clientsMu.Unlock()
+
+	clients[normalizeHost(host)] = c
+}
+
+// ClientFor returns the client for the given registry host, creating it on first use
+func ClientFor(host string) (*Client, error) {
+	if host == "" {
+		host = DefaultHost()
+	}
+	host = normalizeHost(host)
+
+	clientsMu.RLock()
+	c, ok := clients[host]
+	clientsMu.RUnlock()
+	if ok {
+		return c, nil
+	}
+
+	clientsMu.Lock()
+	defer clientsMu.Unlock()
+
+	if c, ok := clients[host]; ok {
+		return c, nil
+	}
+
+	baseURL, ok := config.Hosts[host]
+	if !ok {
+		baseURL = "https://" + host
+	}
+
+	c, err := NewClient(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	if config.UserAgent != "" {
+		c.UserAgent = config.UserAgent
+	}
+	c.Token = config.Tokens[host]
+	if transport != nil {
+		c.HTTPClient = &http.Client{Transport: transport, Timeout: config.Timeout}
+	}
+	c.Cache = diskCache
+	c.Memory = memCache
+
+	clients[host] = c
+
+	return c, nil
+}
+
+// Default returns the client for the configured default registry host
+func Default() (*Client, error) {
+	return ClientFor(DefaultHost())
+}
+
+// DefaultHost returns the configured default registry host
+func DefaultHost() string {
+	clientsMu.RLock()
+	defer clientsMu.RUnlock()
+
+	return config.DefaultHost
+}
+
+func normalizeHost(host string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(host), "/"))
+}
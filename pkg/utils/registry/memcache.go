@@ -0,0 +1,153 @@
+package registry
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	DEFAULT_MEMORY_CACHE_ENTRIES = 256
+	DEFAULT_MEMORY_CACHE_BYTES   = 64 << 20 // 64 MiB
+	DEFAULT_MEMORY_CACHE_TTL     = 5 * time.Minute
+)
+
+// MemoryCache is a size- and TTL-bounded LRU of registry responses that also
+// coalesces concurrent requests for the same key into a single upstream fetch.
+// Returned bodies are shared between callers and must not be modified.
+type MemoryCache struct {
+	maxEntries int
+	maxBytes   int64
+	ttl        time.Duration
+
+	mu    sync.Mutex
+	ll    *list.List
+	items map[string]*list.Element
+	size  int64
+
+	group singleflight.Group
+}
+
+type memoryCacheItem struct {
+	key     string
+	body    []byte
+	expires time.Time
+}
+
+// NewMemoryCache creates an LRU cache. Zero limits disable the corresponding bound.
+func NewMemoryCache(maxEntries int, maxBytes int64, ttl time.Duration) *MemoryCache {
+	return &MemoryCache{
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+		ttl:        ttl,
+		ll:         list.New(),
+		items:      map[string]*list.Element{},
+	}
+}
+
+// Do returns the cached body for key, or calls fetch once for all concurrent
+// callers asking for the same key and caches its result. Each caller stops
+// waiting when its own context is done.
+func (m *MemoryCache) Do(ctx context.Context, key string, fetch func(ctx context.Context) ([]byte, error)) ([]byte, error) {
+	for {
+		if body, ok := m.get(key); ok {
+			return body, nil
+		}
+
+		led := false
+		ch := m.group.DoChan(key, func() (interface{}, error) {
+			led = true
+
+			// Another flight may have filled the cache while this one was waiting
+			if body, ok := m.get(key); ok {
+				return body, nil
+			}
+
+			body, err := fetch(ctx)
+			if err != nil {
+				return nil, err
+			}
+			m.put(key, body)
+
+			return body, nil
+		})
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case res := <-ch:
+			// The caller that started the flight gave up or ran out of time; start a new one with our own context
+			if !led && ctx.Err() == nil && isContextErr(res.Err) {
+				continue
+			}
+			if res.Err != nil {
+				return nil, res.Err
+			}
+			return res.Val.([]byte), nil
+		}
+	}
+}
+
+func isContextErr(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// Len returns the number of cached entries
+func (m *MemoryCache) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.ll.Len()
+}
+
+func (m *MemoryCache) get(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	el, ok := m.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	item := el.Value.(*memoryCacheItem)
+	if m.ttl > 0 && time.Now().After(item.expires) {
+		m.removeElement(el)
+		return nil, false
+	}
+
+	m.ll.MoveToFront(el)
+
+	return item.body, true
+}
+
+func (m *MemoryCache) put(key string, body []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Entries larger than the whole cache would only evict everything else
+	if m.maxBytes > 0 && int64(len(body)) > m.maxBytes {
+		return
+	}
+
+	if el, ok := m.items[key]; ok {
+		m.removeElement(el)
+	}
+
+	item := &memoryCacheItem{key: key, body: body, expires: time.Now().Add(m.ttl)}
+	m.items[key] = m.ll.PushFront(item)
+	m.size += int64(len(body))
+
+	for m.ll.Len() > 0 && ((m.maxEntries > 0 && m.ll.Len() > m.maxEntries) || (m.maxBytes > 0 && m.size > m.maxBytes)) {
+		m.removeElement(m.ll.Back())
+	}
+}
+
+func (m *MemoryCache) removeElement(el *list.Element) {
+	item := m.ll.Remove(el).(*memoryCacheItem)
+	delete(m.items, item.key)
+	m.size -= int64(len(item.body))
+}
@@ -0,0 +1,189 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMemoryCache_Coalescing(t *testing.T) {
+	var requests int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		<-release
+		w.Write([]byte(`{"version":"5.0.0"}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL)
+	if err != nil {
+		t.Fatalf("NewClient() unexpected error: %v", err)
+	}
+	c.Memory = NewMemoryCache(10, 0, time.Minute)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := c.GetSomething(context.Background(), "/v1/providers/hashicorp/aws", nil)
+			errs <- err
+		}()
+	}
+
+	// Give every goroutine time to join the in-flight request
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("GetSomething() unexpected error: %v", err)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("requests = %d, want 1", requests)
+	}
+
+	if _, err := c.GetSomething(context.Background(), "/v1/providers/hashicorp/aws", nil); err != nil {
+		t.Fatalf("GetSomething() unexpected error: %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("requests after cache hit = %d, want 1", requests)
+	}
+}
+
+func TestMemoryCache_Eviction(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemoryCache(2, 10, time.Minute)
+	fetch := func(body string) func(context.Context) ([]byte, error) {
+		return func(context.Context) ([]byte, error) { return []byte(body), nil }
+	}
+
+	m.Do(ctx, "a", fetch("aaaa"))
+	m.Do(ctx, "b", fetch("bbbb"))
+	m.Do(ctx, "a", fetch("xxxx")) // touch a so b is least recently used
+	m.Do(ctx, "c", fetch("cccc"))
+
+	if _, ok := m.get("b"); ok {
+		t.Error("expected b to be evicted by entry limit")
+	}
+	if body, ok := m.get("a"); !ok || string(body) != "aaaa" {
+		t.Errorf("expected a to stay cached, got %q %v", body, ok)
+	}
+
+	m.Do(ctx, "d", fetch("dddddddd"))
+	if m.Len() != 1 {
+		t.Errorf("Len() = %d, want 1 after byte limit eviction", m.Len())
+	}
+
+	m.Do(ctx, "huge", fetch("this body is larger than the cache"))
+	if _, ok := m.get("huge"); ok {
+		t.Error("entries larger than the cache must not be stored")
+	}
+}
+
+func TestMemoryCache_TTLAndErrors(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemoryCache(10, 0, 10*time.Millisecond)
+
+	m.Do(ctx, "k", func(context.Context) ([]byte, error) { return []byte("v"), nil })
+	time.Sleep(20 * time.Millisecond)
+	if _, ok := m.get("k"); ok {
+		t.Error("expected entry to expire")
+	}
+
+	if _, err := m.Do(ctx, "e", func(context.Context) ([]byte, error) { return nil, errors.New("boom") }); err == nil {
+		t.Error("Do() expected error")
+	}
+	if _, ok := m.get("e"); ok {
+		t.Error("errors must not be cached")
+	}
+}
+
+func TestMemoryCache_WaiterSurvivesLeaderCancellation(t *testing.T) {
+	m := NewMemoryCache(10, 0, time.Minute)
+
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+	started := make(chan struct{})
+
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := m.Do(leaderCtx, "k", func(ctx context.Context) ([]byte, error) {
+			close(started)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+		leaderErr <- err
+	}()
+	<-started
+
+	waiterBody := make(chan []byte, 1)
+	go func() {
+		body, _ := m.Do(context.Background(), "k", func(ctx context.Context) ([]byte, error) {
+			return []byte("fresh"), nil
+		})
+		waiterBody <- body
+	}()
+
+	// Let the waiter join the leader's flight before cancelling it
+	time.Sleep(20 * time.Millisecond)
+	cancelLeader()
+
+	if err := <-leaderErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("leader error = %v, want context.Canceled", err)
+	}
+	if body := <-waiterBody; string(body) != "fresh" {
+		t.Errorf("waiter body = %q, want fresh", body)
+	}
+}
+
+func TestMemoryCache_WaiterSurvivesLeaderDeadline(t *testing.T) {
+	m := NewMemoryCache(10, 0, time.Minute)
+
+	leaderCtx, cancelLeader := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancelLeader()
+	started := make(chan struct{})
+
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := m.Do(leaderCtx, "k", func(ctx context.Context) ([]byte, error) {
+			close(started)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+		leaderErr <- err
+	}()
+	<-started
+
+	body, err := m.Do(context.Background(), "k", func(ctx context.Context) ([]byte, error) {
+		return []byte("fresh"), nil
+	})
+	if err != nil || string(body) != "fresh" {
+		t.Errorf("waiter = %q, %v, want fresh", body, err)
+	}
+	if err := <-leaderErr; !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("leader error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestMemoryCache_OwnTimeoutIsNotRetried(t *testing.T) {
+	m := NewMemoryCache(10, 0, time.Minute)
+
+	calls := 0
+	_, err := m.Do(context.Background(), "k", func(ctx context.Context) ([]byte, error) {
+		calls++
+		return nil, context.DeadlineExceeded
+	})
+	if !errors.Is(err, context.DeadlineExceeded) || calls != 1 {
+		t.Errorf("Do() = %v after %d calls, want context.DeadlineExceeded after 1", err, calls)
+	}
+}
@@ -0,0 +1,188 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const DEFAULT_MODULE_SEARCH_LIMIT = 10
+
+// ModuleSearch filters a module search. Without a query, modules are listed
+// by the registry's own ranking.
+type ModuleSearch struct {
+	Query     string
+	Namespace string
+	Provider  string
+	Verified  bool
+	Offset    int
+	Limit     int
+}
+
+// SearchModules searches or lists the modules published on the registry
+func (c *Client) SearchModules(ctx context.Context, search ModuleSearch) (RegistryV1ModuleList, error) {
+	resp := RegistryV1ModuleList{}
+
+	limit := search.Limit
+	if limit <= 0 {
+		limit = DEFAULT_MODULE_SEARCH_LIMIT
+	}
+
+	query := map[string]string{
+		"offset": strconv.Itoa(search.Offset),
+		"limit":  strconv.Itoa(limit),
+	}
+	if search.Provider != "" {
+		query["provider"] = search.Provider
+	}
+	if search.Verified {
+		query["verified"] = "true"
+	}
+
+	path := ""
+	switch {
+	case search.Query != "":
+		path = "search"
+		query["q"] = search.Query
+		if search.Namespace != "" {
+			query["namespace"] = search.Namespace
+		}
+	case search.Namespace != "":
+		path = url.PathEscape(search.Namespace)
+	}
+
+	data, err := c.GetService(ctx, SERVICE_MODULES_V1, path, query)
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// ListModuleVersions returns every published version of a module, newest
+// first. Versions are ProviderVersions so they resolve with the same constraint rules.
+func (c *Client) ListModuleVersions(ctx context.Context, namespace, name, system string) ([]ProviderVersion, error) {
+	path := fmt.Sprintf("%s/%s/%s/versions", url.PathEscape(namespace), url.PathEscape(name), url.PathEscape(system))
+	query := map[string]string{}
+
+	data, err := c.GetService(ctx, SERVICE_MODULES_V1, path, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp RegistryV1ModuleVersions
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, err
+	}
+
+	versions := []ProviderVersion{}
+	for _, module := range resp.Modules {
+		for _, v := range module.Versions {
+			versions = append(versions, ProviderVersion{Version: v.Version})
+		}
+	}
+
+	sortVersions(versions)
+
+	return versions, nil
+}
+
+// ResolveModuleVersion returns the newest published module version matching a
+// Terraform version constraint
+func (c *Client) ResolveModuleVersion(ctx context.Context, namespace, name, system, constraint string) (ProviderVersion, error) {
+	versions, err := c.ListModuleVersions(ctx, namespace, name, system)
+	if err != nil {
+		return ProviderVersion{}, err
+	}
+
+	resolved, err := ResolveVersion(versions, constraint)
+	if err != nil {
+		return ProviderVersion{}, fmt.Errorf("%s/%s/%s: %w", namespace, name, system, err)
+	}
+
+	return resolved, nil
+}
+
+// GetModule returns the inputs, outputs, dependencies, resources, submodules
+// and examples of a module version; the latest version when version is empty
+func (c *Client) GetModule(ctx context.Context, namespace, name, system, version string) (RegistryV1Module, error) {
+	resp := RegistryV1Module{}
+
+	path := fmt.Sprintf("%s/%s/%s", url.PathEscape(namespace), url.PathEscape(name), url.PathEscape(system))
+	if version != "" {
+		path += "/" + url.PathEscape(version)
+	}
+	query := map[string]string{}
+
+	data, err := c.GetService(ctx, SERVICE_MODULES_V1, path, query)
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// ModuleDownloadSource returns the source address a module version is
+// downloaded from, as given by the registry's download endpoint in the
+// X-Terraform-Get header or a JSON "location". Relative addresses are resolved
+// against the endpoint. Download locations are not cached since they may be signed.
+func (c *Client) ModuleDownloadSource(ctx context.Context, namespace, name, system, version string) (string, error) {
+	serviceURL, err := c.ServiceURL(ctx, SERVICE_MODULES_V1)
+	if err != nil {
+		return "", err
+	}
+
+	u := *serviceURL
+	u.Path = strings.TrimSuffix(serviceURL.Path, "/") + "/" + fmt.Sprintf("%s/%s/%s/%s/download", url.PathEscape(namespace), url.PathEscape(name), url.PathEscape(system), url.PathEscape(version))
+
+	req, err := c.newRequest(ctx, &u)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", &StatusError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	location := resp.Header.Get("X-Terraform-Get")
+	if location == "" && len(body) > 0 {
+		var download struct {
+			Location string `json:"location"`
+		}
+		if err := json.Unmarshal(body, &download); err == nil {
+			location = download.Location
+		}
+	}
+	if location == "" {
+		return "", fmt.Errorf("registry did not return a download location for %s/%s/%s %s", namespace, name, system, version)
+	}
+
+	if strings.HasPrefix(location, "/") || strings.HasPrefix(location, "./") || strings.HasPrefix(location, "../") {
+		ref, err := url.Parse(location)
+		if err != nil {
+			return "", fmt.Errorf("invalid download location %q: %w", location, err)
+		}
+		location = u.ResolveReference(ref).String()
+	}
+
+	return location, nil
+}
@@ -0,0 +1,105 @@
+package registry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseModuleAddress(t *testing.T) {
+	tests := []struct {
+		source  string
+		want    string
+		wantErr bool
+	}{
+		{source: "terraform-aws-modules/vpc/aws", want: DEFAULT_REGISTRY_HOST + "/terraform-aws-modules/vpc/aws"},
+		{source: "App.Terraform.io/acme/network/azurerm", want: "app.terraform.io/acme/network/azurerm"},
+		{source: "terraform-aws-modules/vpc", wantErr: true},
+		{source: "terraform-aws-modules//aws", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseModuleAddress(tt.source)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseModuleAddress(%q) expected error but got %s", tt.source, got)
+			}
+			continue
+		}
+		if err != nil || got.String() != tt.want {
+			t.Errorf("ParseModuleAddress(%q) = %s, %v, want %s", tt.source, got, err, tt.want)
+		}
+	}
+}
+
+func TestModules(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/modules/search", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("q") != "vpc" || r.URL.Query().Get("provider") != "aws" || r.URL.Query().Get("limit") != "10" {
+			t.Errorf("unexpected search query: %s", r.URL.RawQuery)
+		}
+		w.Write([]byte(`{"meta":{"limit":10,"current_offset":0,"next_offset":10},"modules":[{"id":"terraform-aws-modules/vpc/aws/5.8.1","namespace":"terraform-aws-modules","name":"vpc","provider":"aws","version":"5.8.1","verified":false}]}`))
+	})
+	mux.HandleFunc("/v1/modules/acme", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"meta":{"limit":10,"current_offset":0},"modules":[{"id":"acme/network/aws/1.0.0","namespace":"acme","name":"network","provider":"aws","version":"1.0.0"}]}`))
+	})
+	mux.HandleFunc("/v1/modules/terraform-aws-modules/vpc/aws/versions", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"modules":[{"source":"terraform-aws-modules/vpc/aws","versions":[{"version":"5.0.0"},{"version":"5.8.1"},{"version":"4.0.2"}]}]}`))
+	})
+	mux.HandleFunc("/v1/modules/terraform-aws-modules/vpc/aws/5.8.1", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":"terraform-aws-modules/vpc/aws/5.8.1","version":"5.8.1","tag":"v5.8.1","root":{"path":"","inputs":[{"name":"cidr","type":"string","default":"\"10.0.0.0/16\""}]},"submodules":[{"path":"modules/vpc-endpoints"}]}`))
+	})
+	mux.HandleFunc("/v1/modules/terraform-aws-modules/vpc/aws/5.8.1/download", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Terraform-Get", "git::https://github.com/terraform-aws-modules/terraform-aws-vpc?ref=v5.8.1")
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("/v1/modules/acme/network/aws/1.0.0/download", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"location":"./archives/network-1.0.0.zip"}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL)
+	if err != nil {
+		t.Fatalf("NewClient() unexpected error: %v", err)
+	}
+	ctx := context.Background()
+
+	list, err := c.SearchModules(ctx, ModuleSearch{Query: "vpc", Provider: "aws"})
+	if err != nil || len(list.Modules) != 1 || list.Meta.NextOffset == nil || *list.Meta.NextOffset != 10 {
+		t.Errorf("SearchModules() = %+v, %v", list, err)
+	}
+
+	list, err = c.SearchModules(ctx, ModuleSearch{Namespace: "acme"})
+	if err != nil || len(list.Modules) != 1 || list.Modules[0].Name != "network" {
+		t.Errorf("SearchModules() without query = %+v, %v", list, err)
+	}
+
+	resolved, err := c.ResolveModuleVersion(ctx, "terraform-aws-modules", "vpc", "aws", "~> 5.0")
+	if err != nil || resolved.Version != "5.8.1" {
+		t.Errorf("ResolveModuleVersion() = %+v, %v, want 5.8.1", resolved, err)
+	}
+
+	module, err := c.GetModule(ctx, "terraform-aws-modules", "vpc", "aws", "5.8.1")
+	if err != nil {
+		t.Fatalf("GetModule() unexpected error: %v", err)
+	}
+	if module.Tag != "v5.8.1" || len(module.Root.Inputs) != 1 || module.Root.Inputs[0].Default != `"10.0.0.0/16"` || len(module.Submodules) != 1 {
+		t.Errorf("GetModule() = %+v", module)
+	}
+
+	location, err := c.ModuleDownloadSource(ctx, "terraform-aws-modules", "vpc", "aws", "5.8.1")
+	if err != nil || location != "git::https://github.com/terraform-aws-modules/terraform-aws-vpc?ref=v5.8.1" {
+		t.Errorf("ModuleDownloadSource() = %q, %v", location, err)
+	}
+
+	location, err = c.ModuleDownloadSource(ctx, "acme", "network", "aws", "1.0.0")
+	if err != nil || location != srv.URL+"/v1/modules/acme/network/aws/1.0.0/archives/network-1.0.0.zip" {
+		t.Errorf("ModuleDownloadSource() with a relative location = %q, %v", location, err)
+	}
+
+	if _, err := c.ModuleDownloadSource(ctx, "acme", "network", "aws", "9.9.9"); err == nil {
+		t.Error("ModuleDownloadSource() expected error for an unknown version")
+	}
+}
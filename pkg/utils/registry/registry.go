@@ -1,13 +1,10 @@
 package registry
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"net/url"
-	"strings"
-	"time"
 
 	"github.com/Yunsang-Jeong/terraform-mcp-server/pkg/utils"
 )
@@ -16,52 +13,24 @@ const (
 	HTTP_TIMEOUT = 10 // seconds
 )
 
-func GetSomethingFromPublicRegistry(path string, query map[string]string) ([]byte, error) {
-	u, err := url.Parse("https://registry.terraform.io")
-	if err != nil {
-		return nil, fmt.Errorf("error parsing terraform registry URL: %w", err)
-	}
-
-	if !strings.HasPrefix(path, "/") {
-		path = "/" + path
-	}
-	u.Path = path
-
-	q := u.Query()
-	for k, v := range query {
-		q.Set(k, v)
-	}
-	u.RawQuery = q.Encode()
-
-	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
-	if err != nil {
-		return nil, fmt.Errorf("build request: %w", err)
-	}
-	req.Header.Set("User-Agent", "terraform-mcp/0.1 (+public-registry)")
-	req.Header.Set("Accept", "application/json")
-
-	client := &http.Client{Timeout: HTTP_TIMEOUT * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("registry error: status=%d body=%s", resp.StatusCode, string(body))
-	}
-
-	return body, nil
+// DOC_CATEGORIES are the provider doc categories published by the registry
+var DOC_CATEGORIES = []string{
+	"overview",
+	"resources",
+	"data-sources",
+	"ephemeral-resources",
+	"list-resources",
+	"functions",
+	"guides",
 }
 
-func GetProvider(namespace, name string) (RegistryV1Provider, error) {
+func (c *Client) GetProvider(ctx context.Context, namespace, name string) (RegistryV1Provider, error) {
 	resp := RegistryV1Provider{}
 
-	path := fmt.Sprintf("/v1/providers/%s/%s", url.PathEscape(namespace), url.PathEscape(name))
+	path := fmt.Sprintf("%s/%s", url.PathEscape(namespace), url.PathEscape(name))
 	query := map[string]string{}
 
-	data, err := GetSomethingFromPublicRegistry(path, query)
+	data, err := c.GetService(ctx, SERVICE_PROVIDERS_V1, path, query)
 	if err != nil {
 		return resp, err
 	}
@@ -73,11 +42,11 @@ func GetProvider(namespace, name string) (RegistryV1Provider, error) {
 	return resp, nil
 }
 
-func GetProviderLatestVersion(namespace, name string) (string, error) {
-	path := fmt.Sprintf("/v1/providers/%s/%s", url.PathEscape(namespace), url.PathEscape(name))
+func (c *Client) GetProviderLatestVersion(ctx context.Context, namespace, name string) (string, error) {
+	path := fmt.Sprintf("%s/%s", url.PathEscape(namespace), url.PathEscape(name))
 	query := map[string]string{}
 
-	data, err := GetSomethingFromPublicRegistry(path, query)
+	data, err := c.GetService(ctx, SERVICE_PROVIDERS_V1, path, query)
 	if err != nil {
 		return "", err
 	}
@@ -90,33 +59,28 @@ func GetProviderLatestVersion(namespace, name string) (string, error) {
 	return resp.Version, nil
 }
 
-func GetProviderVersionId(namespace, name, version string) (string, error) {
-	path := fmt.Sprintf("/v2/providers/%s/%s", url.PathEscape(namespace), url.PathEscape(name))
-	query := map[string]string{
-		"include": "provider-versions",
-	}
-
-	data, err := GetSomethingFromPublicRegistry(path, query)
+func (c *Client) GetProviderVersionId(ctx context.Context, namespace, name, version string) (string, error) {
+	versions, err := c.ListProviderVersions(ctx, namespace, name)
 	if err != nil {
 		return "", err
 	}
 
-	var resp RegistryV2Provider
-	if err := json.Unmarshal(data, &resp); err != nil {
-		return "", err
-	}
-
-	for i := len(resp.Included) - 1; i >= 0; i-- {
-		if resp.Included[i].Attributes.Version == version {
-			return resp.Included[i].ID, nil
+	for _, v := range versions {
+		if v.Version != version {
+			continue
+		}
+		// Versions listed through the v1 protocol have no ID to look docs up with
+		if v.ID == "" {
+			return "", fmt.Errorf("registry %s does not serve provider docs (v2 API)", c.BaseURL.Host)
 		}
+		return v.ID, nil
 	}
 
 	return "", fmt.Errorf("fail to find provider verions id: %s/%s %s", namespace, name, version)
 }
 
-func GetProviderDocsId(versionId, category, slug string) (string, error) {
-	if !utils.IsInList(category, []string{"overview", "resources", "data-sources"}) {
+func (c *Client) GetProviderDocsId(ctx context.Context, versionId, category, slug string) (string, error) {
+	if !utils.IsInList(category, DOC_CATEGORIES) {
 		return "", fmt.Errorf("invalid category: %s", category)
 	}
 
@@ -128,7 +92,7 @@ func GetProviderDocsId(versionId, category, slug string) (string, error) {
 		"filter[language]":         "hcl",
 	}
 
-	data, err := GetSomethingFromPublicRegistry(path, query)
+	data, err := c.GetSomething(ctx, path, query)
 	if err != nil {
 		return "", err
 	}
@@ -145,11 +109,11 @@ func GetProviderDocsId(versionId, category, slug string) (string, error) {
 	return resp.Data[0].ID, nil
 }
 
-func GetProviderDocsContent(docsId string) (string, error) {
+func (c *Client) GetProviderDocsContent(ctx context.Context, docsId string) (string, error) {
 	path := fmt.Sprintf("/v2/provider-docs/%s", docsId)
 	query := map[string]string{}
 
-	data, err := GetSomethingFromPublicRegistry(path, query)
+	data, err := c.GetSomething(ctx, path, query)
 	if err != nil {
 		return "", err
 	}
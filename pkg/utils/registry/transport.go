@@ -0,0 +1,186 @@
+package registry
+
+import (
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	DEFAULT_MAX_RETRIES = 3
+	DEFAULT_MIN_BACKOFF = 500 * time.Millisecond
+	DEFAULT_MAX_BACKOFF = 30 * time.Second
+	DEFAULT_RATE_LIMIT  = 10.0 // requests per second per host
+	DEFAULT_RATE_BURST  = 20
+	DEFAULT_TIMEOUT     = 60 * time.Second
+)
+
+// TransportConfig controls retries and client-side rate limiting of registry requests
+type TransportConfig struct {
+	// MaxRetries is the number of retries after the first attempt
+	MaxRetries int
+	// MinBackoff and MaxBackoff bound the exponential backoff between attempts
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// RateLimit is the sustained number of requests per second allowed per host (0 disables it)
+	RateLimit float64
+	// RateBurst is the number of requests allowed at once before RateLimit applies
+	RateBurst int
+	// AttemptTimeout bounds how long a single attempt waits for response headers
+	AttemptTimeout time.Duration
+}
+
+// DefaultTransportConfig returns the retry and rate-limit settings used by default
+func DefaultTransportConfig() TransportConfig {
+	return TransportConfig{
+		MaxRetries:     DEFAULT_MAX_RETRIES,
+		MinBackoff:     DEFAULT_MIN_BACKOFF,
+		MaxBackoff:     DEFAULT_MAX_BACKOFF,
+		RateLimit:      DEFAULT_RATE_LIMIT,
+		RateBurst:      DEFAULT_RATE_BURST,
+		AttemptTimeout: HTTP_TIMEOUT * time.Second,
+	}
+}
+
+// retryTransport retries transient failures with jittered exponential backoff
+// and throttles requests per host with a token bucket
+type retryTransport struct {
+	base http.RoundTripper
+	cfg  TransportConfig
+
+	limitersMu sync.Mutex
+	limiters   map[string]*rate.Limiter
+}
+
+// NewTransport returns a RoundTripper with connection reuse, retries and rate
+// limiting. It is meant to be shared by every registry client.
+func NewTransport(cfg TransportConfig) http.RoundTripper {
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	base.MaxIdleConnsPerHost = 16
+	base.ResponseHeaderTimeout = cfg.AttemptTimeout
+
+	return &retryTransport{
+		base:     base,
+		cfg:      cfg,
+		limiters: map[string]*rate.Limiter{},
+	}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	for attempt := 0; ; attempt++ {
+		if limiter := t.limiter(req.URL.Host); limiter != nil {
+			if err := limiter.Wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err := t.base.RoundTrip(req)
+		if attempt >= t.cfg.MaxRetries || !t.retryable(req, resp, err) {
+			return resp, err
+		}
+
+		wait := t.backoff(attempt, resp)
+		if resp != nil {
+			// Drain the body so the connection can be reused
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (t *retryTransport) retryable(req *http.Request, resp *http.Response, err error) bool {
+	// Requests with a body cannot be replayed safely
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+
+	if err != nil {
+		// Unknown hosts will not appear by retrying
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return false
+		}
+		// Other network errors are transient unless the caller gave up
+		return req.Context().Err() == nil
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
+
+// backoff returns the delay before the next attempt, preferring the server's Retry-After
+func (t *retryTransport) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			return min(wait, t.cfg.MaxBackoff)
+		}
+	}
+
+	delay := t.cfg.MinBackoff << attempt
+	if delay <= 0 || delay > t.cfg.MaxBackoff {
+		delay = t.cfg.MaxBackoff
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	// Equal jitter keeps at least half the delay while spreading out concurrent retries
+	half := delay / 2
+	return half + rand.N(half+1)
+}
+
+func (t *retryTransport) limiter(host string) *rate.Limiter {
+	if t.cfg.RateLimit <= 0 {
+		return nil
+	}
+
+	t.limitersMu.Lock()
+	defer t.limitersMu.Unlock()
+
+	limiter, ok := t.limiters[host]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Limit(t.cfg.RateLimit), max(t.cfg.RateBurst, 1))
+		t.limiters[host] = limiter
+	}
+
+	return limiter
+}
+
+// parseRetryAfter accepts both delay-seconds and HTTP-date forms
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+
+	return 0, false
+}
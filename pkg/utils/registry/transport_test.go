@@ -0,0 +1,125 @@
+package registry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTransport_RetriesTransientErrors(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&requests, 1) {
+		case 1:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer srv.Close()
+
+	c := newTransportTestClient(t, srv.URL, TransportConfig{MaxRetries: 3, MinBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond})
+
+	if _, err := c.GetSomething(context.Background(), "/v1/providers/hashicorp/aws", nil); err != nil {
+		t.Fatalf("GetSomething() unexpected error: %v", err)
+	}
+	if requests != 3 {
+		t.Errorf("requests = %d, want 3", requests)
+	}
+}
+
+func TestTransport_GivesUpAfterMaxRetries(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	c := newTransportTestClient(t, srv.URL, TransportConfig{MaxRetries: 2, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond})
+
+	if _, err := c.GetSomething(context.Background(), "/v1/providers/hashicorp/aws", nil); err == nil {
+		t.Fatal("GetSomething() expected error")
+	}
+	if requests != 3 {
+		t.Errorf("requests = %d, want 3", requests)
+	}
+}
+
+func TestTransport_DoesNotRetryClientErrors(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	c := newTransportTestClient(t, srv.URL, TransportConfig{MaxRetries: 3, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond})
+
+	c.GetSomething(context.Background(), "/v1/providers/hashicorp/missing", nil)
+	if requests != 1 {
+		t.Errorf("requests = %d, want 1", requests)
+	}
+}
+
+func TestTransport_RateLimit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	c := newTransportTestClient(t, srv.URL, TransportConfig{RateLimit: 20, RateBurst: 1})
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := c.GetSomething(context.Background(), "/v1/providers/hashicorp/aws", nil); err != nil {
+			t.Fatalf("GetSomething() unexpected error: %v", err)
+		}
+	}
+
+	// One token is available immediately, the other two arrive every 50ms
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("3 requests took %v, expected rate limiting to slow them down", elapsed)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{value: "", ok: false},
+		{value: "7", want: 7 * time.Second, ok: true},
+		{value: now.Add(90 * time.Second).Format(http.TimeFormat), want: 90 * time.Second, ok: true},
+		{value: now.Add(-time.Minute).Format(http.TimeFormat), want: 0, ok: true},
+		{value: "soon", ok: false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.value, now)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %v, %v; want %v, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func newTransportTestClient(t *testing.T, baseURL string, cfg TransportConfig) *Client {
+	t.Helper()
+
+	c, err := NewClient(baseURL)
+	if err != nil {
+		t.Fatalf("NewClient() unexpected error: %v", err)
+	}
+	c.HTTPClient = &http.Client{Transport: NewTransport(cfg), Timeout: 5 * time.Second}
+
+	return c
+}
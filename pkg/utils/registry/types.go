@@ -83,6 +83,15 @@ type RegistryV2Provider struct {
 
 type RegistryV2ProviderDocs struct {
 	Data []RegistryV2ProviderDoc `json:"data"`
+	Meta struct {
+		Pagination struct {
+			PageSize    int  `json:"page-size"`
+			CurrentPage int  `json:"current-page"`
+			NextPage    *int `json:"next-page"`
+			TotalPages  int  `json:"total-pages"`
+			TotalCount  int  `json:"total-count"`
+		} `json:"pagination"`
+	} `json:"meta"`
 }
 
 type RegistryV2ProviderDoc struct {
@@ -123,3 +132,99 @@ type RegistryV2ProviderDocsIdData struct {
 		Self string `json:"self"`
 	} `json:"links"`
 }
+
+type RegistryV1ProviderVersions struct {
+	ID       string `json:"id"`
+	Versions []struct {
+		Version   string   `json:"version"`
+		Protocols []string `json:"protocols"`
+	} `json:"versions"`
+}
+
+type RegistryV1ModuleList struct {
+	Meta struct {
+		Limit         int    `json:"limit"`
+		CurrentOffset int    `json:"current_offset"`
+		NextOffset    *int   `json:"next_offset"`
+		NextURL       string `json:"next_url"`
+	} `json:"meta"`
+	Modules []RegistryV1ModuleSummary `json:"modules"`
+}
+
+type RegistryV1ModuleSummary struct {
+	ID          string    `json:"id"`
+	Owner       string    `json:"owner"`
+	Namespace   string    `json:"namespace"`
+	Name        string    `json:"name"`
+	Version     string    `json:"version"`
+	Provider    string    `json:"provider"`
+	Description string    `json:"description"`
+	Source      string    `json:"source"`
+	PublishedAt time.Time `json:"published_at"`
+	Downloads   int64     `json:"downloads"`
+	Verified    bool      `json:"verified"`
+}
+
+type RegistryV1Module struct {
+	RegistryV1ModuleSummary
+	Tag        string                 `json:"tag"`
+	Providers  []string               `json:"providers"`
+	Versions   []string               `json:"versions"`
+	Root       RegistryV1ModuleBody   `json:"root"`
+	Submodules []RegistryV1ModuleBody `json:"submodules"`
+	Examples   []RegistryV1ModuleBody `json:"examples"`
+}
+
+// RegistryV1ModuleBody describes the root module, a submodule or an example of a module version
+type RegistryV1ModuleBody struct {
+	Path                 string                               `json:"path"`
+	Name                 string                               `json:"name"`
+	Readme               string                               `json:"readme"`
+	Empty                bool                                 `json:"empty"`
+	Inputs               []RegistryV1ModuleInput              `json:"inputs"`
+	Outputs              []RegistryV1ModuleOutput             `json:"outputs"`
+	Dependencies         []RegistryV1ModuleDependency         `json:"dependencies"`
+	ProviderDependencies []RegistryV1ModuleProviderDependency `json:"provider_dependencies"`
+	Resources            []RegistryV1ModuleResource           `json:"resources"`
+}
+
+type RegistryV1ModuleInput struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	// Default is JSON encoded, e.g. "\"10.0.0.0/16\"" or "[]"; empty when the input has none
+	Default  string `json:"default"`
+	Required bool   `json:"required"`
+}
+
+type RegistryV1ModuleOutput struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type RegistryV1ModuleDependency struct {
+	Name    string `json:"name"`
+	Source  string `json:"source"`
+	Version string `json:"version,omitempty"`
+}
+
+type RegistryV1ModuleProviderDependency struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Source    string `json:"source"`
+	Version   string `json:"version"`
+}
+
+type RegistryV1ModuleResource struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+type RegistryV1ModuleVersions struct {
+	Modules []struct {
+		Source   string `json:"source"`
+		Versions []struct {
+			Version string `json:"version"`
+		} `json:"versions"`
+	} `json:"modules"`
+}
@@ -0,0 +1,183 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-version"
+)
+
+// ProviderVersion is a published version of a provider
+type ProviderVersion struct {
+	// ID is the registry's provider-version ID used to look up versioned docs
+	ID          string    `json:"id,omitempty"`
+	Version     string    `json:"version"`
+	PublishedAt time.Time `json:"published_at,omitzero"`
+}
+
+// ListProviderVersions returns every published version of a provider, newest first
+func (c *Client) ListProviderVersions(ctx context.Context, namespace, name string) ([]ProviderVersion, error) {
+	versions, err := c.listProviderVersionsV2(ctx, namespace, name)
+
+	// Registries other than the public one usually only implement the v1 protocol
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+		versions, err = c.listProviderVersionsV1(ctx, namespace, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sortVersions(versions)
+
+	return versions, nil
+}
+
+func (c *Client) listProviderVersionsV2(ctx context.Context, namespace, name string) ([]ProviderVersion, error) {
+	path := fmt.Sprintf("/v2/providers/%s/%s", url.PathEscape(namespace), url.PathEscape(name))
+	query := map[string]string{
+		"include": "provider-versions",
+	}
+
+	data, err := c.GetSomething(ctx, path, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp RegistryV2Provider
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, err
+	}
+
+	versions := []ProviderVersion{}
+	for _, included := range resp.Included {
+		if included.Type != "" && included.Type != "provider-versions" {
+			continue
+		}
+		versions = append(versions, ProviderVersion{
+			ID:          included.ID,
+			Version:     included.Attributes.Version,
+			PublishedAt: included.Attributes.PublishedAt,
+		})
+	}
+
+	return versions, nil
+}
+
+func (c *Client) listProviderVersionsV1(ctx context.Context, namespace, name string) ([]ProviderVersion, error) {
+	path := fmt.Sprintf("%s/%s/versions", url.PathEscape(namespace), url.PathEscape(name))
+	query := map[string]string{}
+
+	data, err := c.GetService(ctx, SERVICE_PROVIDERS_V1, path, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp RegistryV1ProviderVersions
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, err
+	}
+
+	versions := []ProviderVersion{}
+	for _, v := range resp.Versions {
+		versions = append(versions, ProviderVersion{Version: v.Version})
+	}
+
+	return versions, nil
+}
+
+// ResolveProviderVersion returns the newest published version matching a
+// Terraform version constraint such as "~> 5.0" or ">= 4.2, < 6". An empty
+// constraint or "latest" selects the newest release.
+func (c *Client) ResolveProviderVersion(ctx context.Context, namespace, name, constraint string) (ProviderVersion, error) {
+	versions, err := c.ListProviderVersions(ctx, namespace, name)
+	if err != nil {
+		return ProviderVersion{}, err
+	}
+
+	resolved, err := ResolveVersion(versions, constraint)
+	if err != nil {
+		return ProviderVersion{}, fmt.Errorf("%s/%s: %w", namespace, name, err)
+	}
+
+	return resolved, nil
+}
+
+// ResolveVersion picks the newest version matching the constraint
+func ResolveVersion(versions []ProviderVersion, constraint string) (ProviderVersion, error) {
+	matching, err := MatchingVersions(versions, constraint)
+	if err != nil {
+		return ProviderVersion{}, err
+	}
+
+	if len(matching) == 0 {
+		if constraint = strings.TrimSpace(constraint); constraint == "" {
+			constraint = "latest"
+		}
+		return ProviderVersion{}, fmt.Errorf("no version matches %q", constraint)
+	}
+
+	return matching[0], nil
+}
+
+// MatchingVersions returns the versions allowed by a Terraform version
+// constraint, newest first. As in Terraform, pre-releases only match when the
+// constraint names them exactly, and an empty constraint or "latest" matches
+// every release.
+func MatchingVersions(versions []ProviderVersion, constraint string) ([]ProviderVersion, error) {
+	constraint = strings.TrimSpace(constraint)
+
+	var constraints version.Constraints
+	if constraint != "" && constraint != "latest" {
+		var err error
+		if constraints, err = version.NewConstraint(constraint); err != nil {
+			return nil, fmt.Errorf("invalid version constraint %q: %w", constraint, err)
+		}
+	}
+
+	sorted := append([]ProviderVersion(nil), versions...)
+	sortVersions(sorted)
+
+	matching := []ProviderVersion{}
+	for _, candidate := range sorted {
+		v, err := version.NewVersion(candidate.Version)
+		if err != nil {
+			continue
+		}
+
+		if (constraints == nil && v.Prerelease() == "") || (constraints != nil && constraints.Check(v)) {
+			matching = append(matching, candidate)
+		}
+	}
+
+	return matching, nil
+}
+
+// sortVersions orders versions newest first; unparsable versions go last
+func sortVersions(versions []ProviderVersion) {
+	parsed := make(map[string]*version.Version, len(versions))
+	for _, v := range versions {
+		if pv, err := version.NewVersion(v.Version); err == nil {
+			parsed[v.Version] = pv
+		}
+	}
+
+	sort.SliceStable(versions, func(i, j int) bool {
+		vi, vj := parsed[versions[i].Version], parsed[versions[j].Version]
+		switch {
+		case vi == nil:
+			return false
+		case vj == nil:
+			return true
+		default:
+			return vi.GreaterThan(vj)
+		}
+	})
+}
@@ -0,0 +1,106 @@
+package registry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestResolveVersion(t *testing.T) {
+	versions := []ProviderVersion{
+		{Version: "4.2.0"}, {Version: "5.0.0"}, {Version: "5.31.0"}, {Version: "4.67.0"},
+		{Version: "6.0.0-beta1"}, {Version: "5.100.0"}, {Version: "3.76.1"},
+	}
+
+	tests := []struct {
+		constraint string
+		want       string
+		wantErr    bool
+	}{
+		{constraint: "", want: "5.100.0"},
+		{constraint: "latest", want: "5.100.0"},
+		{constraint: "5.0.0", want: "5.0.0"},
+		{constraint: "~> 5.0", want: "5.100.0"},
+		{constraint: "~> 4.2.0", want: "4.2.0"},
+		{constraint: ">= 4.2, < 5", want: "4.67.0"},
+		{constraint: "6.0.0-beta1", want: "6.0.0-beta1"},
+		{constraint: "> 7", wantErr: true},
+		{constraint: "~> banana", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			got, err := ResolveVersion(versions, tt.constraint)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ResolveVersion() expected error but got %s", got.Version)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveVersion() unexpected error: %v", err)
+			}
+			if got.Version != tt.want {
+				t.Errorf("ResolveVersion() = %s, want %s", got.Version, tt.want)
+			}
+		})
+	}
+}
+
+func TestListProviderVersions(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/providers/hashicorp/aws", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"included":[
+			{"type":"provider-versions","id":"1","attributes":{"version":"4.0.0","published-at":"2022-02-10T00:00:00Z"}},
+			{"type":"provider-versions","id":"2","attributes":{"version":"5.0.0","published-at":"2023-05-25T00:00:00Z"}}
+		]}`))
+	})
+	mux.HandleFunc("/v1/providers/acme/widget/versions", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"versions":[{"version":"0.9.0"},{"version":"1.1.0"},{"version":"1.0.0"}]}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL)
+	if err != nil {
+		t.Fatalf("NewClient() unexpected error: %v", err)
+	}
+
+	versions, err := c.ListProviderVersions(context.Background(), "hashicorp", "aws")
+	if err != nil {
+		t.Fatalf("ListProviderVersions() unexpected error: %v", err)
+	}
+	if len(versions) != 2 || versions[0].Version != "5.0.0" || versions[0].ID != "2" || versions[0].PublishedAt.Year() != 2023 {
+		t.Errorf("unexpected v2 versions: %+v", versions)
+	}
+
+	// Without the v2 API the v1 versions endpoint is used
+	resolved, err := c.ResolveProviderVersion(context.Background(), "acme", "widget", "~> 1.0")
+	if err != nil {
+		t.Fatalf("ResolveProviderVersion() unexpected error: %v", err)
+	}
+	if resolved.Version != "1.1.0" {
+		t.Errorf("resolved = %s, want 1.1.0", resolved.Version)
+	}
+}
+
+func TestGetProviderVersionId_V1OnlyRegistry(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/providers/acme/widget/versions", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"versions":[{"version":"1.0.0"}]}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL)
+	if err != nil {
+		t.Fatalf("NewClient() unexpected error: %v", err)
+	}
+
+	_, err = c.GetProviderVersionId(context.Background(), "acme", "widget", "1.0.0")
+	if err == nil || !strings.Contains(err.Error(), "does not serve provider docs (v2 API)") {
+		t.Errorf("GetProviderVersionId() error = %v, want the missing v2 API reported", err)
+	}
+}
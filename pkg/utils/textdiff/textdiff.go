@@ -0,0 +1,115 @@
+package textdiff
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5/utils/diff"
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// DIFF_TIMEOUT bounds the time spent diffing; past it the rest is reported as replaced
+const DIFF_TIMEOUT = 10 * time.Second
+
+type line struct {
+	op   byte
+	text string
+	// oldLine and newLine are the 1-based positions of the line in each text; a line
+	// missing from a text has the position of the line that follows it there
+	oldLine, newLine int
+}
+
+// Unified returns the unified diff turning from into to, with context lines
+// around each change, or an empty string when the texts are equal. A negative
+// context is treated as zero.
+func Unified(fromName, toName, from, to string, context int) string {
+	context = max(context, 0)
+	lines := diffLines(from, to)
+
+	changes := []int{}
+	for i, l := range lines {
+		if l.op != ' ' {
+			changes = append(changes, i)
+		}
+	}
+	if len(changes) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", fromName, toName)
+
+	for i := 0; i < len(changes); {
+		// Changes closer than twice the context share a hunk
+		j := i
+		for j+1 < len(changes) && changes[j+1]-changes[j] <= 2*context+1 {
+			j++
+		}
+
+		start := max(changes[i]-context, 0)
+		end := min(changes[j]+context+1, len(lines))
+		writeHunk(&b, lines[start:end])
+
+		i = j + 1
+	}
+
+	return b.String()
+}
+
+func diffLines(from, to string) []line {
+	lines := []line{}
+	oldLine, newLine := 1, 1
+
+	for _, d := range diff.DoWithTimeout(from, to, DIFF_TIMEOUT) {
+		for _, text := range strings.SplitAfter(d.Text, "\n") {
+			if text == "" {
+				continue
+			}
+
+			switch d.Type {
+			case diffmatchpatch.DiffEqual:
+				lines = append(lines, line{op: ' ', text: text, oldLine: oldLine, newLine: newLine})
+				oldLine, newLine = oldLine+1, newLine+1
+			case diffmatchpatch.DiffDelete:
+				lines = append(lines, line{op: '-', text: text, oldLine: oldLine, newLine: newLine})
+				oldLine++
+			case diffmatchpatch.DiffInsert:
+				lines = append(lines, line{op: '+', text: text, oldLine: oldLine, newLine: newLine})
+				newLine++
+			}
+		}
+	}
+
+	return lines
+}
+
+func writeHunk(b *strings.Builder, hunk []line) {
+	oldCount, newCount := 0, 0
+	for _, l := range hunk {
+		if l.op != '+' {
+			oldCount++
+		}
+		if l.op != '-' {
+			newCount++
+		}
+	}
+
+	// An empty range is numbered after the line it follows
+	oldStart, newStart := hunk[0].oldLine, hunk[0].newLine
+	if oldCount == 0 {
+		oldStart--
+	}
+	if newCount == 0 {
+		newStart--
+	}
+
+	fmt.Fprintf(b, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount)
+	for _, l := range hunk {
+		b.WriteByte(l.op)
+		b.WriteString(l.text)
+		if !strings.HasSuffix(l.text, "\n") {
+			b.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
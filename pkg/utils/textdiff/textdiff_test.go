@@ -0,0 +1,69 @@
+package textdiff
+
+import (
+	"testing"
+)
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		context  int
+		want     string
+	}{
+		{
+			name: "equal",
+			from: "a\nb\n",
+			to:   "a\nb\n",
+			want: "",
+		},
+		{
+			name:    "change with context",
+			from:    "1\n2\n3\n4\n5\n6\n7\n8\n",
+			to:      "1\n2\n3\nfour\n5\n6\n7\n8\n",
+			context: 1,
+			want:    "--- a\n+++ b\n@@ -3,3 +3,3 @@\n 3\n-4\n+four\n 5\n",
+		},
+		{
+			name:    "separate hunks",
+			from:    "1\n2\n3\n4\n5\n6\n7\n8\n",
+			to:      "one\n2\n3\n4\n5\n6\n7\neight\n",
+			context: 1,
+			want:    "--- a\n+++ b\n@@ -1,2 +1,2 @@\n-1\n+one\n 2\n@@ -7,2 +7,2 @@\n 7\n-8\n+eight\n",
+		},
+		{
+			name:    "insertion without context",
+			from:    "1\n2\n",
+			to:      "1\nnew\n2\n",
+			context: 0,
+			want:    "--- a\n+++ b\n@@ -1,0 +2,1 @@\n+new\n",
+		},
+		{
+			name:    "change without context",
+			from:    "1\n2\n3\n",
+			to:      "1\ntwo\n3\n",
+			context: 0,
+			want:    "--- a\n+++ b\n@@ -2,1 +2,1 @@\n-2\n+two\n",
+		},
+		{
+			name:    "negative context",
+			from:    "1\n2\n3\n",
+			to:      "1\ntwo\n3\n",
+			context: -5,
+			want:    "--- a\n+++ b\n@@ -2,1 +2,1 @@\n-2\n+two\n",
+		},
+		{
+			name:    "missing newline",
+			from:    "a\n",
+			to:      "a\nb",
+			context: 1,
+			want:    "--- a\n+++ b\n@@ -1,1 +1,2 @@\n a\n+b\n\\ No newline at end of file\n",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := Unified("a", "b", tt.from, tt.to, tt.context); got != tt.want {
+			t.Errorf("Unified() %s = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -8,3 +8,41 @@ func IsInList(val string, allowed []string) bool {
 	}
 	return false
 }
+
+// Closest returns the candidate within a few edits of name, for "did you mean"
+// hints on misspelled names
+func Closest(name string, candidates []string) (string, bool) {
+	best, bestDistance := "", 3
+	for _, candidate := range candidates {
+		if distance := Levenshtein(name, candidate); distance < bestDistance || (distance == bestDistance && candidate < best) {
+			best, bestDistance = candidate, distance
+		}
+	}
+
+	return best, best != ""
+}
+
+// Levenshtein is the number of single rune edits turning a into b
+func Levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
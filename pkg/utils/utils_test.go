@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestClosest(t *testing.T) {
+	candidates := []string{"bucket", "bucket_prefix", "tags", "tags_all"}
+
+	tests := []struct {
+		name  string
+		want  string
+		found bool
+	}{
+		{"buckt", "bucket", true},
+		{"tag", "tags", true},
+		{"tags_al", "tags_all", true},
+		{"versioning", "", false},
+	}
+
+	for _, tt := range tests {
+		if got, found := Closest(tt.name, candidates); got != tt.want || found != tt.found {
+			t.Errorf("Closest(%q) = %q, %v, want %q, %v", tt.name, got, found, tt.want, tt.found)
+		}
+	}
+}